@@ -0,0 +1,390 @@
+// Package amazon reads the order and item CSV files of Amazon's order history
+// reports into the orders model. Only shipped orders and items are read, and
+// both are keyed by Order.Key, ready for orders.Merge.
+//
+// Importing the package registers the "amazon" source, which reads and merges
+// both files, overlaying the payments of any saved invoices on them.
+package amazon
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dbinit/ynab-amazon-import/orders"
+	"github.com/go-openapi/strfmt"
+)
+
+// Name is the name of the Amazon source.
+const Name = "amazon"
+
+// Retailer holds the defaults of Amazon orders.
+var Retailer = orders.Retailer{
+	Payee:        "Amazon",
+	ImportPrefix: "AMZ:",
+	OrderURL:     "https://amzn.com/order-details/?orderID=",
+}
+
+func init() {
+	orders.Register(Name, Source{})
+}
+
+const (
+	// Amazon CSV date format.
+	dateFormat = "01/02/06"
+
+	// CSV column names.
+	shipmentDate    = "Shipment Date"
+	orderID         = "Order ID"
+	orderStatus     = "Order Status"
+	shippingCharge  = "Shipping Charge"
+	totalPromotions = "Total Promotions"
+	taxCharged      = "Tax Charged"
+	totalCharged    = "Total Charged"
+	title           = "Title"
+	seller          = "Seller"
+	itemSubtotalTax = "Item Subtotal Tax"
+	itemTotal       = "Item Total"
+
+	// Optional order CSV column names.
+	paymentInstrument = "Payment Instrument Type"
+
+	// Optional item CSV column names.
+	quantity      = "Quantity"
+	purchasePrice = "Purchase Price Per Unit"
+	asinISBN      = "ASIN/ISBN"
+	category      = "Category"
+	unspscCode    = "UNSPSC Code"
+
+	// Shipped "Order Status" value.
+	shipped = "Shipped"
+)
+
+// Purchasing detail column names of Amazon Business accounts, as named in the
+// Amazon Business report and then in the order history CSVs.
+var (
+	poNumberColumns     = []string{"PO Number", "Purchase Order Number"}
+	accountGroupColumns = []string{"Account Group", "Group Name"}
+	buyerColumns        = []string{"Buyer Name"}
+	approverColumns     = []string{"Approver", "Approver Name"}
+)
+
+// Matches the sellers of grocery delivery orders.
+var grocerySellerRE = regexp.MustCompile(`(?i)\b(?:whole foods|amazon ?fresh)\b`)
+
+// Matches the mention of Subscribe & Save in emails and invoices of its
+// deliveries.
+var subscribeAndSaveRE = regexp.MustCompile(`(?i)\bsubscribe\s*(?:&|and)\s*save\b`)
+
+// ColumnsError reports required columns missing from a CSV header.
+type ColumnsError struct {
+	Missing []string
+}
+
+func (e *ColumnsError) Error() string {
+	return fmt.Sprintf("missing columns %s", strings.Join(e.Missing, ", "))
+}
+
+// ParseError reports a value that couldn't be parsed. Line is the CSV line
+// number, counting the header as line 1.
+type ParseError struct {
+	Line   int
+	Column string
+	Value  string
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s %q: %v", e.Line, e.Column, e.Value, e.Err)
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// ParseOrders reads an Amazon order CSV and returns an order for each shipment.
+// Errors are a *ColumnsError, a *ParseError or a CSV read error.
+func ParseOrders(r io.Reader) (map[string]*orders.Order, error) {
+	rows, err := parseCSV(r, orderStatus, orderID, shipmentDate, shippingCharge, totalPromotions, taxCharged, totalCharged)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make(map[string]*orders.Order)
+	for _, row := range rows {
+		// Skip orders that haven't shipped.
+		if row.values[orderStatus] != shipped {
+			continue
+		}
+
+		// Get or add an order record.
+		od, err := row.order(details)
+		if err != nil {
+			return nil, err
+		}
+
+		// Business orders have purchasing details.
+		row.purchasing(od)
+
+		// Parse the order amounts.
+		amounts := []*int64{&od.ShippingCharge, &od.TotalPromotions, &od.TaxCharged, &od.TotalCharged}
+		var charged int64
+		for i, col := range []string{shippingCharge, totalPromotions, taxCharged, totalCharged} {
+			n, err := row.money(col, col != totalPromotions)
+			if err != nil {
+				return nil, err
+			}
+			*amounts[i] += n
+			if col == totalCharged {
+				charged = n
+			}
+		}
+
+		// Break the charge down by payment instrument, if the export has it.
+		if v := strings.TrimSpace(row.values[paymentInstrument]); v != "" {
+			addPayment(od, v, charged)
+		}
+	}
+
+	return details, nil
+}
+
+// ParseItems reads an Amazon item CSV and returns an order for each shipment,
+// with the items' tax and totals. Errors are as for ParseOrders.
+func ParseItems(r io.Reader) (map[string]*orders.Order, error) {
+	rows, err := parseCSV(r, orderStatus, orderID, shipmentDate, title, seller, itemSubtotalTax, itemTotal)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make(map[string]*orders.Order)
+	for _, row := range rows {
+		// Skip items that haven't shipped.
+		if row.values[orderStatus] != shipped {
+			continue
+		}
+
+		// Get or add an order record.
+		od, err := row.order(details)
+		if err != nil {
+			return nil, err
+		}
+
+		// Create an item record.
+		it := &orders.Item{
+			Title:    row.values[title],
+			Seller:   row.values[seller],
+			Quantity: 1,
+			ASIN:     row.values[asinISBN],
+			Category: row.values[category],
+			UNSPSC:   row.values[unspscCode],
+		}
+
+		// Parse the optional quantity and unit price.
+		if v := row.values[quantity]; v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, &ParseError{Line: row.line, Column: quantity, Value: v, Err: err}
+			}
+			it.Quantity = n
+		}
+		if row.values[purchasePrice] != "" {
+			if it.UnitPrice, err = row.money(purchasePrice, false); err != nil {
+				return nil, err
+			}
+		}
+
+		// Parse the item amounts.
+		amounts := []*int64{&it.SubtotalTax, &it.Total}
+		for i, col := range []string{itemSubtotalTax, itemTotal} {
+			if *amounts[i], err = row.money(col, true); err != nil {
+				return nil, err
+			}
+		}
+
+		// Add the item and amounts to the order.
+		od.Grocery = od.Grocery || grocerySellerRE.MatchString(it.Seller)
+		od.TaxCharged += it.SubtotalTax
+		od.TotalCharged += it.Total
+		od.Items = append(od.Items, it)
+	}
+
+	return details, nil
+}
+
+// Source is the Amazon source, reading the order and item CSV files.
+type Source struct{}
+
+// Retailer returns the Amazon defaults.
+func (Source) Retailer() orders.Retailer { return Retailer }
+
+// Load parses the order and item CSV files, which are both required, and
+// merges them. If Files.Invoices is set, the invoices' payments are overlaid
+// on the merged orders with OverlayInvoices.
+func (Source) Load(files orders.Files) (map[string]*orders.Order, *orders.MergeReport, error) {
+	if files.Orders == "" || files.Items == "" {
+		return nil, nil, errors.New("the Amazon source needs both the orders and items CSV files")
+	}
+	odm, err := ParseOrdersFile(files.Orders)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse orders CSV: %w", err)
+	}
+	idm, err := ParseItemsFile(files.Items)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse items CSV: %w", err)
+	}
+	merged, report := orders.Merge(odm, idm)
+	if files.Invoices != "" {
+		if err := OverlayInvoices(merged, report, files.Invoices); err != nil {
+			return nil, nil, err
+		}
+	}
+	return merged, report, nil
+}
+
+// ParseOrdersFile reads an Amazon order CSV file with ParseOrders.
+func ParseOrdersFile(name string) (map[string]*orders.Order, error) {
+	return parseFile(name, ParseOrders)
+}
+
+// ParseItemsFile reads an Amazon item CSV file with ParseItems.
+func ParseItemsFile(name string) (map[string]*orders.Order, error) {
+	return parseFile(name, ParseItems)
+}
+
+// parseFile opens a file and parses it.
+func parseFile(name string, parse func(io.Reader) (map[string]*orders.Order, error)) (odm map[string]*orders.Order, err error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("os.Open(%q): %w", name, err)
+	}
+	defer func() {
+		if ferr := f.Close(); ferr != nil && err == nil {
+			err = fmt.Errorf("(os.File).Close(%q): %w", name, ferr)
+		}
+	}()
+	if odm, err = parse(f); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return odm, nil
+}
+
+// row is a CSV row mapping column names to values.
+type row struct {
+	line   int
+	values map[string]string
+}
+
+// parseCSV reads a CSV and extracts all columns into a row for each line. The
+// named columns are required.
+func parseCSV(r io.Reader, cols ...string) ([]*row, error) {
+	reader := csv.NewReader(r)
+
+	// Read the header row.
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	// Build a column index map.
+	sort.Strings(cols)
+	colm := make(map[int]string)
+	for i, c := range header {
+		colm[i] = c
+		// Find each required column name. Column names must match exactly.
+		if j := sort.SearchStrings(cols, c); j < len(cols) && cols[j] == c {
+			cols = append(cols[:j], cols[j+1:]...)
+		}
+	}
+	if len(cols) > 0 {
+		return nil, &ColumnsError{Missing: cols}
+	}
+
+	// Build row maps of column names to values.
+	var rows []*row
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rows: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		rm := &row{line: line, values: make(map[string]string)}
+		for i, c := range colm {
+			if len(fields) > i {
+				rm.values[c] = fields[i]
+			}
+		}
+		rows = append(rows, rm)
+	}
+	return rows, nil
+}
+
+// order returns the order of the row's order ID and shipment date, adding it
+// to details if there isn't one.
+func (r *row) order(details map[string]*orders.Order) (*orders.Order, error) {
+	v := r.values[shipmentDate]
+	d, err := time.ParseInLocation(dateFormat, v, time.Local)
+	if err != nil {
+		return nil, &ParseError{Line: r.line, Column: shipmentDate, Value: v, Err: err}
+	}
+	od := orders.GetOrAdd(details, r.values[orderID], strfmt.Date(d))
+	od.Retailer = Retailer
+	od.URL = Retailer.OrderURL + od.ID
+	return od, nil
+}
+
+// money parses a currency column of the row.
+func (r *row) money(col string, invert bool) (int64, error) {
+	n, err := orders.ParseMoney(r.values[col], invert)
+	if err != nil {
+		return 0, &ParseError{Line: r.line, Column: col, Value: r.values[col], Err: err}
+	}
+	return n, nil
+}
+
+// purchasing sets an order's purchasing details that aren't set yet from the
+// row's purchasing detail columns, if it has them.
+func (r *row) purchasing(od *orders.Order) {
+	for _, f := range []struct {
+		v    *string
+		cols []string
+	}{
+		{&od.PONumber, poNumberColumns},
+		{&od.AccountGroup, accountGroupColumns},
+		{&od.Buyer, buyerColumns},
+		{&od.Approver, approverColumns},
+	} {
+		for _, col := range f.cols {
+			if *f.v == "" {
+				*f.v = r.values[col]
+			}
+		}
+	}
+}
+
+// addPayment adds an amount paid with an instrument, e.g. "Visa - 1234", to an
+// order's payments. Orders paid partly by gift card list both instruments,
+// e.g. "Gift Certificate/Card and Visa - 1234", without the split, so only
+// instruments that are gift cards alone count as gift cards.
+func addPayment(od *orders.Order, instrument string, amount int64) {
+	for _, p := range od.Payments {
+		if p.Instrument == instrument {
+			p.Amount += amount
+			return
+		}
+	}
+	lower := strings.ToLower(instrument)
+	od.Payments = append(od.Payments, &orders.Payment{
+		Instrument: instrument,
+		GiftCard:   strings.HasPrefix(lower, "gift") && !strings.Contains(lower, " and "),
+		Amount:     amount,
+	})
+}
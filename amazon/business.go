@@ -0,0 +1,239 @@
+package amazon
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dbinit/ynab-amazon-import/orders"
+	"github.com/go-openapi/strfmt"
+)
+
+// BusinessName is the name of the Amazon Business source.
+const BusinessName = "amazon-business"
+
+func init() {
+	orders.Register(BusinessName, BusinessSource{})
+}
+
+const (
+	// Amazon Business CSV date format.
+	businessDateFormat = "01/02/2006"
+
+	// Amazon Business CSV column names.
+	bizShipShipping  = "Shipment Shipping & Handling"
+	bizShipPromotion = "Shipment Promotion"
+	bizShipTax       = "Shipment Tax"
+	bizShipTotal     = "Shipment Net Total"
+	bizCategory      = "Product Category"
+	bizASIN          = "ASIN"
+	bizUNSPSC        = "UNSPSC"
+	bizItemQuantity  = "Item Quantity"
+	bizItemPrice     = "Purchase PPU"
+	bizItemSubtotal  = "Item Subtotal"
+	bizItemTax       = "Item Tax"
+	bizSellerName    = "Seller Name"
+	bizPaymentRef    = "Payment Reference ID"
+	bizPaymentAmount = "Payment Amount"
+	bizPaymentID     = "Payment Identifier"
+)
+
+// BusinessSource is the Amazon Business source, reading the orders and
+// shipments CSV report named by Files.Orders.
+type BusinessSource struct{}
+
+// Retailer returns the Amazon defaults.
+func (BusinessSource) Retailer() orders.Retailer { return Retailer }
+
+// Load parses the report with ParseBusinessFile and merges its shipments and
+// items.
+func (BusinessSource) Load(files orders.Files) (map[string]*orders.Order, *orders.MergeReport, error) {
+	if files.Orders == "" {
+		return nil, nil, errors.New("the Amazon Business source needs the orders and shipments CSV file")
+	}
+	odm, idm, err := ParseBusinessFile(files.Orders)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse Amazon Business CSV: %w", err)
+	}
+	merged, report := orders.Merge(odm, idm)
+	return merged, report, nil
+}
+
+// ParseBusinessFile reads an Amazon Business CSV file with ParseBusiness.
+func ParseBusinessFile(name string) (odm, idm map[string]*orders.Order, err error) {
+	odm, err = parseFile(name, func(r io.Reader) (map[string]*orders.Order, error) {
+		shipments, items, err := ParseBusiness(r)
+		idm = items
+		return shipments, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return odm, idm, nil
+}
+
+// ParseBusiness reads an Amazon Business orders and shipments CSV report,
+// which has a row for each item of a shipment, repeating the shipment amounts
+// and the order's PO number, account group, buyer and approver. It returns
+// the shipments and their items keyed by Order.Key, ready for orders.Merge.
+// Rows without a shipment date haven't shipped and are skipped.
+//
+// A shipment paid in several payments repeats its items for each payment, so
+// only the rows of a shipment's first payment add items, and the others only
+// add their payment. Errors are as for ParseOrders.
+func ParseBusiness(r io.Reader) (odm, idm map[string]*orders.Order, err error) {
+	rows, err := parseCSV(r, orderID, shipmentDate, bizShipShipping, bizShipPromotion, bizShipTax, bizShipTotal, title, bizItemSubtotal, bizItemTax)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	odm = make(map[string]*orders.Order)
+	idm = make(map[string]*orders.Order)
+	firstPayment := make(map[string]string)
+	payments := make(map[string]bool)
+	for _, row := range rows {
+		// Skip items that haven't shipped.
+		if row.values[shipmentDate] == "" {
+			continue
+		}
+		d, err := row.businessDate()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Get or add the shipment, whose amounts are on each of its rows.
+		od := orders.GetOrAdd(odm, row.values[orderID], d)
+		ref := row.values[bizPaymentRef]
+		first, seen := firstPayment[od.Key()]
+		if !seen {
+			firstPayment[od.Key()] = ref
+			row.business(od)
+			amounts := []*int64{&od.ShippingCharge, &od.TotalPromotions, &od.TaxCharged, &od.TotalCharged}
+			for i, col := range []string{bizShipShipping, bizShipPromotion, bizShipTax, bizShipTotal} {
+				n, err := row.businessMoney(col)
+				if err != nil {
+					return nil, nil, err
+				}
+				*amounts[i] = -n
+			}
+			// Promotions may be exported as discounts or as negative amounts.
+			if od.TotalPromotions < 0 {
+				od.TotalPromotions = -od.TotalPromotions
+			}
+		}
+
+		// Only the rows of the shipment's first payment add items.
+		if err := row.payment(od, payments); err != nil {
+			return nil, nil, err
+		}
+		if seen && ref != first {
+			continue
+		}
+
+		// Create an item record.
+		group := orders.GetOrAdd(idm, od.ID, d)
+		row.business(group)
+		it := &orders.Item{
+			Title:    row.values[title],
+			Seller:   row.values[bizSellerName],
+			Quantity: 1,
+			ASIN:     row.values[bizASIN],
+			Category: row.values[bizCategory],
+			UNSPSC:   row.values[bizUNSPSC],
+		}
+		if v := row.values[bizItemQuantity]; v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, nil, &ParseError{Line: row.line, Column: bizItemQuantity, Value: v, Err: err}
+			}
+			it.Quantity = n
+		}
+		if row.values[bizItemPrice] != "" {
+			if it.UnitPrice, err = row.businessMoney(bizItemPrice); err != nil {
+				return nil, nil, err
+			}
+		}
+		subtotal, err := row.businessMoney(bizItemSubtotal)
+		if err != nil {
+			return nil, nil, err
+		}
+		tax, err := row.businessMoney(bizItemTax)
+		if err != nil {
+			return nil, nil, err
+		}
+		it.SubtotalTax = -tax
+		it.Total = -(subtotal + tax)
+
+		// Add the item and amounts to the item group.
+		group.Grocery = group.Grocery || grocerySellerRE.MatchString(it.Seller)
+		group.TaxCharged += it.SubtotalTax
+		group.TotalCharged += it.Total
+		group.Items = append(group.Items, it)
+	}
+	return odm, idm, nil
+}
+
+// businessDate parses the row's shipment date, in either the Amazon Business
+// or the order history format.
+func (r *row) businessDate() (strfmt.Date, error) {
+	v := r.values[shipmentDate]
+	d, err := time.ParseInLocation(businessDateFormat, v, time.Local)
+	if err != nil {
+		if d, err = time.ParseInLocation(dateFormat, v, time.Local); err != nil {
+			return strfmt.Date{}, &ParseError{Line: r.line, Column: shipmentDate, Value: v, Err: err}
+		}
+	}
+	return strfmt.Date(d), nil
+}
+
+// business sets an order's retailer, URL and purchasing details from the row.
+func (r *row) business(od *orders.Order) {
+	od.Retailer = Retailer
+	od.URL = Retailer.OrderURL + od.ID
+	r.purchasing(od)
+}
+
+// businessMoney parses a currency column of the row, which may have thousands
+// separators. Empty amounts are zero.
+func (r *row) businessMoney(col string) (int64, error) {
+	v := strings.ReplaceAll(strings.TrimSpace(r.values[col]), ",", "")
+	if v == "" {
+		return 0, nil
+	}
+	n, err := orders.ParseMoney(v, false)
+	if err != nil {
+		return 0, &ParseError{Line: r.line, Column: col, Value: r.values[col], Err: err}
+	}
+	return n, nil
+}
+
+// payment adds the row's payment to an order, unless an earlier row of the
+// shipment added it.
+func (r *row) payment(od *orders.Order, seen map[string]bool) error {
+	if r.values[bizPaymentAmount] == "" {
+		return nil
+	}
+	k := od.Key() + "\x00" + r.values[bizPaymentRef] + "\x00" + r.values[bizPaymentID]
+	if seen[k] {
+		return nil
+	}
+	seen[k] = true
+	n, err := r.businessMoney(bizPaymentAmount)
+	if err != nil {
+		return err
+	}
+	typ := r.values[paymentInstrument]
+	instrument := typ
+	if id := r.values[bizPaymentID]; id != "" {
+		instrument = fmt.Sprintf("%s ending in %s", typ, id)
+	}
+	od.Payments = append(od.Payments, &orders.Payment{
+		Instrument: strings.TrimSpace(instrument),
+		GiftCard:   strings.Contains(strings.ToLower(typ), "gift"),
+		Amount:     -n,
+	})
+	return nil
+}
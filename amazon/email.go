@@ -0,0 +1,477 @@
+package amazon
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dbinit/ynab-amazon-import/orders"
+	"github.com/go-openapi/strfmt"
+)
+
+// EmailName is the name of the Amazon email source.
+const EmailName = "amazon-email"
+
+func init() {
+	orders.Register(EmailName, EmailSource{})
+}
+
+// EmailSource is the Amazon email source, reading order confirmation and
+// shipment emails from a .eml file, an mbox file, or a Maildir or folder of
+// .eml files named by Files.Orders.
+type EmailSource struct{}
+
+// Retailer returns the Amazon defaults.
+func (EmailSource) Retailer() orders.Retailer { return Retailer }
+
+// Load reads the emails with LoadEmails.
+func (EmailSource) Load(files orders.Files) (map[string]*orders.Order, *orders.MergeReport, error) {
+	if files.Orders == "" {
+		return nil, nil, errors.New("the Amazon email source needs a mailbox or email file")
+	}
+	return LoadEmails(files.Orders)
+}
+
+// LoadEmails reads Amazon's order confirmation and shipment emails from a
+// .eml file, an mbox file, or a Maildir or folder of .eml files. Each shipment
+// email is an order shipment dated by the email, and orders with no shipment
+// email are read from their confirmation. Emails that aren't from Amazon, or
+// are about something else, are ignored, and order emails that can't be
+// parsed are reported as DiagUnparsed diagnostics. Only reading the files
+// returns an error.
+func LoadEmails(name string) (map[string]*orders.Order, *orders.MergeReport, error) {
+	msgs, err := readMailbox(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var unparsed []*orders.Diagnostic
+	shipments := make(map[string][]*receipt)
+	confirmations := make(map[string]*receipt)
+	seen := make(map[string]bool)
+	for _, m := range msgs {
+		e, err := parseEmail(m.raw)
+		if err != nil {
+			unparsed = append(unparsed, &orders.Diagnostic{Kind: orders.DiagUnparsed, Detail: fmt.Sprintf("%s: %v", m.name, err)})
+			continue
+		}
+		if e.messageID != "" {
+			if seen[e.messageID] {
+				continue
+			}
+			seen[e.messageID] = true
+		}
+		r, err := parseReceipt(e)
+		if err != nil {
+			d := &orders.Diagnostic{Kind: orders.DiagUnparsed, Detail: fmt.Sprintf("%s: %q: %v", m.name, e.subject, err)}
+			if r != nil {
+				d.OrderID = r.orderID
+			}
+			unparsed = append(unparsed, d)
+			continue
+		}
+		switch {
+		case r == nil:
+		case r.shipped:
+			shipments[r.orderID] = append(shipments[r.orderID], r)
+		case confirmations[r.orderID] == nil || r.date.After(confirmations[r.orderID].date):
+			// A later confirmation replaces an earlier one for a changed order.
+			confirmations[r.orderID] = r
+		}
+	}
+
+	odm := make(map[string]*orders.Order)
+	idm := make(map[string]*orders.Order)
+	var unshipped []*orders.Order
+	for _, id := range orders.SortedKeys(confirmations) {
+		if len(shipments[id]) == 0 {
+			unshipped = append(unshipped, confirmations[id].add(odm, idm))
+		}
+	}
+	for _, id := range orders.SortedKeys(shipments) {
+		for _, r := range shipments[id] {
+			r.add(odm, idm)
+		}
+	}
+
+	merged, report := orders.Merge(odm, idm)
+	for _, od := range unshipped {
+		report.Diagnostics = append(report.Diagnostics, &orders.Diagnostic{
+			Kind:         orders.DiagUnshipped,
+			OrderID:      od.ID,
+			ShipmentDate: od.ShipmentDate.String(),
+			Amount:       od.TotalCharged,
+			Detail:       "no shipment email; using the order confirmation, dated by the order",
+		})
+	}
+	report.Diagnostics = append(report.Diagnostics, unparsed...)
+	return merged, report, nil
+}
+
+// receipt holds the order amounts and items read from an email. Amounts are
+// positive milliunits, as shown in the email.
+type receipt struct {
+	orderID string
+	shipped bool
+	date    time.Time
+
+	subtotal, shipping, promotions, tax, total int64
+	items                                      []*receiptItem
+
+	// grocery is set for grocery delivery orders, and tip and fees are their
+	// delivery tip and other fees.
+	grocery   bool
+	tip, fees int64
+
+	// subscribe is set for Subscribe & Save deliveries.
+	subscribe bool
+
+	// giftCard is the part of the total paid by gift card, and instrument
+	// the instrument paying the rest, if known.
+	giftCard   int64
+	instrument string
+}
+
+// receiptItem is an item of a receipt.
+type receiptItem struct {
+	title    string
+	seller   string
+	quantity int64
+	price    int64
+	refunded bool
+}
+
+// Memos of the fees of grocery orders.
+const (
+	tipMemo  = "Tip"
+	feesMemo = "Fees"
+)
+
+// Labels of the summary amounts of an email.
+const (
+	labelIgnore = iota
+	labelSubtotal
+	labelShipping
+	labelPromotion
+	labelTax
+	labelTotal
+	labelGiftCard
+	labelTip
+	labelFee
+)
+
+// summaryLabels maps the lower case prefixes of summary lines to their
+// amounts. More specific prefixes come first.
+var summaryLabels = []struct {
+	prefix string
+	label  int
+}{
+	{"total before tax", labelIgnore},
+	{"gift card", labelGiftCard},
+	{"reward", labelIgnore},
+	{"item subtotal", labelSubtotal},
+	{"items subtotal", labelSubtotal},
+	{"item(s) subtotal", labelSubtotal},
+	{"subtotal", labelSubtotal},
+	{"shipping & handling", labelShipping},
+	{"shipping and handling", labelShipping},
+	{"shipping", labelShipping},
+	{"free shipping", labelPromotion},
+	{"promotion", labelPromotion},
+	{"your coupon savings", labelPromotion},
+	{"coupon", labelPromotion},
+	{"discount", labelPromotion},
+	{"driver tip", labelTip},
+	{"delivery tip", labelTip},
+	{"courier tip", labelTip},
+	{"tip", labelTip},
+	{"bag fee", labelFee},
+	{"service fee", labelFee},
+	{"delivery fee", labelFee},
+	{"estimated tax", labelTax},
+	{"tax", labelTax},
+	{"order total", labelTotal},
+	{"shipment total", labelTotal},
+	{"grand total", labelTotal},
+	{"total", labelTotal},
+}
+
+var (
+	// Matches an order ID, including digital order IDs.
+	orderIDRE = regexp.MustCompile(`\b(?:\d{3}|D\d{2})-\d{7}-\d{7}\b`)
+	// Matches a line ending in an amount, e.g. "Order Total: $13.36".
+	amountLineRE = regexp.MustCompile(`^(.*?)[\s:]*(-?\s*\$\s*[\d,]+\.\d{2})(?:\s+each)?$`)
+	// Matches a quantity line, e.g. "Quantity: 2".
+	quantityRE = regexp.MustCompile(`(?i)^(?:quantity|qty)\s*:?\s*(\d+)$`)
+	// Matches a seller line, e.g. "Sold by: Amazon.com Services LLC".
+	soldByRE = regexp.MustCompile(`(?i)^sold by\s*:?\s*(.+)$`)
+	// Matches a line marking an item as refunded or substituted, e.g.
+	// "Refunded" or "Substituted with: Organic Bananas".
+	refundedRE = regexp.MustCompile(`(?i)^(?:refunded|not delivered|unavailable|out of stock|substituted|replaced)\b`)
+	// Matches item detail and delivery lines, which aren't item titles.
+	itemNoiseRE = regexp.MustCompile(`(?i)^(?:condition|color|size|style|format|edition|arriving|delivered|estimated delivery|delivery|ship to|shipped to|ship speed|gift|return|view|track|manage)\b|https?://`)
+)
+
+// parseReceipt reads the order of a confirmation or shipment email. It returns
+// nil for other emails, and an error for order emails it can't read, with the
+// receipt if the order ID is known.
+func parseReceipt(e *email) (*receipt, error) {
+	subject := strings.ToLower(e.subject)
+	ids := orderIDRE.FindAllString(e.subject+"\n"+e.text, -1)
+	if !strings.Contains(strings.ToLower(e.from), "amazon.") && !orderIDRE.MatchString(e.subject) {
+		return nil, nil
+	}
+	r := &receipt{
+		date:      e.date,
+		grocery:   grocerySellerRE.MatchString(e.from + "\n" + e.subject),
+		subscribe: subscribeAndSaveRE.MatchString(e.subject + "\n" + e.text),
+	}
+	switch {
+	case strings.Contains(subject, "shipped"):
+		r.shipped = true
+	case strings.Contains(subject, "order confirmation"), strings.Contains(subject, "ordered:"),
+		strings.HasPrefix(subject, "your amazon") && strings.Contains(subject, "order of"):
+	default:
+		return nil, nil
+	}
+	if len(ids) == 0 {
+		return nil, errors.New("no order ID")
+	}
+	r.orderID = ids[0]
+	for _, id := range ids[1:] {
+		if id != r.orderID {
+			return r, fmt.Errorf("the email covers more than one order, %s and %s", r.orderID, id)
+		}
+	}
+
+	var (
+		inItems  = false
+		pending  = -1
+		haveSum  = make(map[int]bool)
+		title    string
+		seller   string
+		quantity int64 = 1
+		refunded bool
+	)
+	for _, line := range strings.Split(e.text, "\n") {
+		line = strings.TrimSpace(strings.ReplaceAll(line, "\u00a0", " "))
+		if line == "" {
+			continue
+		}
+		if !inItems {
+			// Items follow the order ID.
+			inItems = strings.Contains(line, r.orderID)
+			continue
+		}
+
+		label, amount := line, ""
+		if m := amountLineRE.FindStringSubmatch(line); m != nil {
+			label, amount = m[1], m[2]
+		}
+		i := summaryLabel(label, amount == "")
+		if amount != "" && label == "" {
+			// An amount on the line after its label.
+			i = pending
+		}
+		pending = -1
+		if i >= 0 {
+			if amount == "" {
+				pending = i
+				continue
+			}
+			n, err := parseAmount(amount)
+			if err != nil {
+				return r, err
+			}
+			if l := summaryLabels[i].label; l != labelIgnore && !haveSum[l] {
+				// The first amount of each kind wins over any repeats.
+				haveSum[l] = true
+				*r.amount(l) += n
+			}
+			continue
+		}
+		if len(haveSum) > 0 {
+			// Items come before the summary.
+			continue
+		}
+
+		switch m := quantityRE.FindStringSubmatch(line); {
+		case m != nil:
+			quantity, _ = strconv.ParseInt(m[1], 10, 64)
+		case soldByRE.MatchString(line):
+			seller = soldByRE.FindStringSubmatch(line)[1]
+			r.grocery = r.grocery || grocerySellerRE.MatchString(seller)
+		case refundedRE.MatchString(line):
+			refunded = true
+		case amount != "" && label == "" && title != "":
+			n, err := parseAmount(amount)
+			if err != nil {
+				return r, err
+			}
+			r.items = append(r.items, &receiptItem{title: title, seller: seller, quantity: quantity, price: n, refunded: refunded})
+			title, seller, quantity, refunded = "", "", 1, false
+		case amount == "" && !itemNoiseRE.MatchString(line) && !orderIDRE.MatchString(line):
+			title = line
+		}
+	}
+	if !haveSum[labelTotal] {
+		return r, errors.New("no order total")
+	}
+
+	// Item prices are unit prices, unless only the line totals add up to the
+	// subtotal.
+	var unitSum, lineSum int64
+	for _, it := range r.items {
+		unitSum += it.price * it.quantity
+		lineSum += it.price
+	}
+	if haveSum[labelSubtotal] && lineSum == r.subtotal && unitSum != r.subtotal {
+		for _, it := range r.items {
+			it.price /= it.quantity
+		}
+		unitSum = lineSum
+	}
+	if !haveSum[labelSubtotal] {
+		r.subtotal = unitSum
+	}
+	return r, nil
+}
+
+// summaryLabel returns the index of the summary label a line starts with, or
+// -1. A label with no amount must match exactly, so as not to mistake item
+// titles for labels.
+func summaryLabel(line string, exact bool) int {
+	line = strings.ToLower(strings.TrimRight(line, ": "))
+	for i, l := range summaryLabels {
+		if exact && line == l.prefix || !exact && strings.HasPrefix(line, l.prefix) && len(line) <= len(l.prefix)+16 {
+			return i
+		}
+	}
+	return -1
+}
+
+// amount returns the receipt amount for a summary label.
+func (r *receipt) amount(label int) *int64 {
+	switch label {
+	case labelSubtotal:
+		return &r.subtotal
+	case labelShipping:
+		return &r.shipping
+	case labelPromotion:
+		return &r.promotions
+	case labelTax:
+		return &r.tax
+	case labelGiftCard:
+		return &r.giftCard
+	case labelTip:
+		return &r.tip
+	case labelFee:
+		return &r.fees
+	}
+	return &r.total
+}
+
+// shareOf returns the share of an amount for a part of a whole, rounded to
+// the cent.
+func shareOf(amount, part, whole int64) int64 {
+	n := float64(amount) * float64(part) / float64(whole) / 10
+	return int64(math.Round(n)) * 10
+}
+
+// parseAmount parses a displayed amount, e.g. "$1,234.56", as positive
+// milliunits.
+func parseAmount(s string) (int64, error) {
+	n, err := orders.ParseMoney(strings.NewReplacer(",", "", " ", "", "-", "").Replace(s), false)
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// add adds the receipt's order amounts to odm and its items to idm, returning
+// the order. Tax is shared between the items and any shipping by amount, and
+// the items' share between the items by price.
+func (r *receipt) add(odm, idm map[string]*orders.Order) *orders.Order {
+	y, m, d := r.date.In(time.Local).Date()
+	date := strfmt.Date(time.Date(y, m, d, 0, 0, 0, 0, time.Local))
+
+	od := orders.GetOrAdd(odm, r.orderID, date)
+	od.Retailer = Retailer
+	od.URL = Retailer.OrderURL + od.ID
+	od.ShippingCharge -= r.shipping
+	od.TotalPromotions += r.promotions
+	od.TaxCharged -= r.tax
+	od.TotalCharged -= r.total
+	od.Grocery = od.Grocery || r.grocery
+	od.SubscribeAndSave = od.SubscribeAndSave || r.subscribe
+	for _, f := range []struct {
+		memo   string
+		amount int64
+	}{{tipMemo, r.tip}, {feesMemo, r.fees}} {
+		if f.amount != 0 {
+			od.Fees = append(od.Fees, &orders.Fee{Memo: f.memo, Amount: -f.amount})
+		}
+	}
+	if r.giftCard != 0 || r.instrument != "" {
+		instrument := r.instrument
+		if instrument == "" {
+			instrument = "Card"
+		}
+		if card := r.total - r.giftCard; card != 0 {
+			od.Payments = append(od.Payments, &orders.Payment{Instrument: instrument, Amount: -card})
+		}
+		if r.giftCard != 0 {
+			od.Payments = append(od.Payments, &orders.Payment{Instrument: "Gift Card", GiftCard: true, Amount: -r.giftCard})
+		}
+	}
+	if len(r.items) == 0 {
+		return od
+	}
+
+	// Promotions offset shipping first, e.g. for free shipping.
+	itemsTax := r.tax
+	if shipping := r.shipping - r.promotions; shipping > 0 && r.subtotal+shipping > 0 {
+		itemsTax = shareOf(r.tax, r.subtotal, r.subtotal+shipping)
+	}
+	id := orders.GetOrAdd(idm, r.orderID, date)
+	id.Retailer, id.URL = od.Retailer, od.URL
+	id.Grocery = id.Grocery || r.grocery
+	id.SubscribeAndSave = id.SubscribeAndSave || r.subscribe
+	last := -1
+	for i, it := range r.items {
+		if !it.refunded {
+			last = i
+		}
+	}
+	remaining := itemsTax
+	for i, it := range r.items {
+		line := it.price * it.quantity
+		item := &orders.Item{
+			Title:     it.title,
+			Seller:    it.seller,
+			Quantity:  it.quantity,
+			UnitPrice: it.price,
+			Total:     -line,
+			Refunded:  it.refunded,
+		}
+		id.Items = append(id.Items, item)
+		if it.refunded {
+			// Refunded items aren't part of the charge, or taxed.
+			continue
+		}
+		tax := remaining
+		if i < last && r.subtotal > 0 {
+			tax = shareOf(itemsTax, line, r.subtotal)
+		}
+		remaining -= tax
+		item.SubtotalTax = -tax
+		item.Total -= tax
+		id.TaxCharged += item.SubtotalTax
+		id.TotalCharged += item.Total
+	}
+	return od
+}
@@ -0,0 +1,340 @@
+package amazon
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dbinit/ynab-amazon-import/orders"
+)
+
+// summarize describes the orders, a line for each order and its items, and
+// the report's diagnostics, for comparing with what a test expects.
+func summarize(odm map[string]*orders.Order, report *orders.MergeReport) (lines, diags []string) {
+	for _, k := range orders.SortedKeys(odm) {
+		od := odm[k]
+		lines = append(lines, fmt.Sprintf("%s %s ship %d promo %d tax %d total %d", od.ShipmentDate, od.ID, od.ShippingCharge, od.TotalPromotions, od.TaxCharged, od.TotalCharged))
+		for _, p := range od.Payments {
+			lines = append(lines, fmt.Sprintf("  paid %d by %s", p.Amount, p.Instrument))
+		}
+		for _, it := range od.Items {
+			lines = append(lines, fmt.Sprintf("  %s | %s | %d x %d | tax %d | total %d", it.Title, it.Seller, it.Quantity, it.UnitPrice, it.SubtotalTax, it.Total))
+		}
+	}
+	if report != nil {
+		for _, d := range report.Diagnostics {
+			diags = append(diags, strings.TrimSpace(d.Kind+" "+d.OrderID))
+		}
+	}
+	return lines, diags
+}
+
+// diff returns a description of the differences between two lists of lines,
+// or "" if they're equal.
+func diff(got, want []string) string {
+	if strings.Join(got, "\n") == strings.Join(want, "\n") {
+		return ""
+	}
+	return fmt.Sprintf("got:\n\t%s\nwant:\n\t%s", strings.Join(got, "\n\t"), strings.Join(want, "\n\t"))
+}
+
+func TestSummaryLabel(t *testing.T) {
+	for _, tc := range []struct {
+		line  string
+		exact bool
+		want  int
+	}{
+		{"Order Total:", true, labelTotal},
+		{"Total before tax", false, labelIgnore},
+		{"Item(s) Subtotal", false, labelSubtotal},
+		{"Shipping & Handling", false, labelShipping},
+		{"Free Shipping:", true, labelPromotion},
+		{"Your Coupon Savings", false, labelPromotion},
+		{"Estimated tax to be collected", false, labelTax},
+		{"Gift Card Amount", false, labelGiftCard},
+		{"Delivery Tip", true, labelTip},
+		{"Bag Fee", false, labelFee},
+		// Item titles without amounts must match a label exactly.
+		{"Tax Software 2023", true, -1},
+		{"Shipping Boxes, 12 Pack", true, -1},
+		// Lines with amounts may add a little to a label, but not a lot.
+		{"Subtotal for the items of this rather long shipment", false, -1},
+	} {
+		t.Run(tc.line, func(t *testing.T) {
+			got := summaryLabel(tc.line, tc.exact)
+			if got >= 0 {
+				got = summaryLabels[got].label
+			}
+			if got != tc.want {
+				t.Errorf("summaryLabel(%q, %v) is label %d, want %d", tc.line, tc.exact, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestShareOf(t *testing.T) {
+	for _, tc := range []struct {
+		amount, part, whole, want int64
+	}{
+		{1000, 1, 3, 330},
+		{1000, 2, 3, 670},
+		{-1000, 1, 3, -330},
+		{3000, 25000, 30000, 2500},
+		{5190, 39900, 57900, 3580},
+		// Half cents round away from zero.
+		{50, 1, 2, 30},
+		{-50, 1, 2, -30},
+		{0, 1, 2, 0},
+	} {
+		if got := shareOf(tc.amount, tc.part, tc.whole); got != tc.want {
+			t.Errorf("shareOf(%d, %d, %d) = %d, want %d", tc.amount, tc.part, tc.whole, got, tc.want)
+		}
+	}
+}
+
+func TestParseReceipt(t *testing.T) {
+	const amazon = "Amazon.com <shipment-tracking@amazon.com>"
+	for _, tc := range []struct {
+		name, from, subject, text string
+		// want describes the receipt, or is "" for emails that aren't order
+		// emails.
+		want, err string
+	}{
+		{
+			name: "not from Amazon", from: "Friend <friend@example.com>", subject: "Lunch",
+			text: "Order #111-0000000-0000001\nOrder Total: $1.00",
+		},
+		{
+			name: "not an order email", from: amazon, subject: "Your Amazon.com password was changed",
+			text: "Order #111-0000000-0000001\nOrder Total: $1.00",
+		},
+		{
+			name: "unit prices", from: amazon, subject: "Shipped: \"Pens\"",
+			text: "Order #111-0000000-0000001\nPens\nQuantity: 2\n$5.00\nItem Subtotal: $10.00\nOrder Total: $10.00",
+			want: "111-0000000-0000001 shipped sub 10000 ship 0 promo 0 tax 0 total 10000 gift 0: Pens||2|5000",
+		},
+		{
+			name: "line totals", from: amazon, subject: "Shipped: \"Pens\"",
+			text: "Order #111-0000000-0000001\nPens\nQuantity: 2\n$10.00\nItem Subtotal: $10.00\nOrder Total: $10.00",
+			want: "111-0000000-0000001 shipped sub 10000 ship 0 promo 0 tax 0 total 10000 gift 0: Pens||2|5000",
+		},
+		{
+			name: "no subtotal", from: amazon, subject: "Shipped: \"Pens\"",
+			text: "Order #111-0000000-0000001\nPens\nQty: 3\n$2.00 each\nOrder Total: $6.00",
+			want: "111-0000000-0000001 shipped sub 6000 ship 0 promo 0 tax 0 total 6000 gift 0: Pens||3|2000",
+		},
+		{
+			name: "amounts after their labels", from: amazon, subject: "Your Amazon.com order of \"Tax Software 2023\".",
+			text: "Order #111-0000000-0000001\nTax Software 2023\nSold by: Intuit\n$40.00\nSubtotal:\n$40.00\nTax\n$3.20\nGift Card Amount: -$13.20\nOrder Total:\n$43.20",
+			want: "111-0000000-0000001 confirmed sub 40000 ship 0 promo 0 tax 3200 total 43200 gift 13200: Tax Software 2023|Intuit|1|40000",
+		},
+		{
+			name: "repeated summary", from: amazon, subject: "Shipped: \"Pens\"",
+			text: "Order #111-0000000-0000001\nPens\n$1.00\nOrder Total: $1.00\nOrder #111-0000000-0000001\nPens\n$1.00\nOrder Total: $1.00",
+			want: "111-0000000-0000001 shipped sub 1000 ship 0 promo 0 tax 0 total 1000 gift 0: Pens||1|1000",
+		},
+		{
+			name: "refunded item", from: amazon, subject: "Shipped: Whole Foods Market order",
+			text: "Order #111-0000000-0000001\nBananas\n$1.50\nAvocados\nRefunded\n$4.00\nDriver Tip: $5.00\nOrder Total: $6.50",
+			want: "111-0000000-0000001 shipped sub 5500 ship 0 promo 0 tax 0 total 6500 gift 0: Bananas||1|1500; Avocados||1|4000 refunded",
+		},
+		{
+			name: "subscribe & save", from: amazon, subject: "Your Subscribe & Save delivery has shipped",
+			text: "Order #111-0000000-0000001\nCoffee Pods\n$20.00\nOrder Total: $20.00",
+			want: "111-0000000-0000001 shipped subscribe & save sub 20000 ship 0 promo 0 tax 0 total 20000 gift 0: Coffee Pods||1|20000",
+		},
+		{
+			name: "no order ID", from: amazon, subject: "Shipped: \"Pens\"",
+			text: "Pens\n$1.00\nOrder Total: $1.00",
+			err:  "no order ID",
+		},
+		{
+			name: "no total", from: amazon, subject: "Shipped: \"Pens\"",
+			text: "Order #111-0000000-0000001\nPens\n$1.00",
+			err:  "no order total",
+		},
+		{
+			name: "two orders", from: amazon, subject: "Your Amazon.com order of \"Pens\" and 1 more item.",
+			text: "Order #111-0000000-0000001\nPens\n$1.00\nOrder #111-0000000-0000002\nInk\n$2.00\nOrder Total: $3.00",
+			err:  "the email covers more than one order, 111-0000000-0000001 and 111-0000000-0000002",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := parseReceipt(&email{from: tc.from, subject: tc.subject, date: time.Now(), text: tc.text})
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("parseReceipt() = %v, want error %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseReceipt() = %v", err)
+			}
+			var got string
+			if r != nil {
+				kind := "confirmed"
+				if r.shipped {
+					kind = "shipped"
+				}
+				if r.subscribe {
+					kind += " subscribe & save"
+				}
+				var items []string
+				for _, it := range r.items {
+					s := fmt.Sprintf("%s|%s|%d|%d", it.title, it.seller, it.quantity, it.price)
+					if it.refunded {
+						s += " refunded"
+					}
+					items = append(items, s)
+				}
+				got = fmt.Sprintf("%s %s sub %d ship %d promo %d tax %d total %d gift %d: %s",
+					r.orderID, kind, r.subtotal, r.shipping, r.promotions, r.tax, r.total, r.giftCard, strings.Join(items, "; "))
+			}
+			if got != tc.want {
+				t.Errorf("parseReceipt() =\n\t%s\nwant\n\t%s", got, tc.want)
+			}
+		})
+	}
+}
+
+// Orders of the email fixtures.
+var (
+	// Merge adds the tax on shipping to the shipping charge.
+	confirmationOrder = []string{
+		"2023-01-02 111-1111111-1111111 ship -5500 promo 0 tax -3000 total -33000",
+		"  AA Batteries 24 Pack | Amazon.com Services LLC | 2 x 10000 | tax -2000 | total -22000",
+		"  USB Cable | Cable Co | 1 x 5000 | tax -500 | total -5500",
+	}
+	// The plain text part wins over the HTML part.
+	quotedPrintableOrder = []string{
+		"2023-01-03 111-2222222-2222222 ship 0 promo 0 tax -2700 total -47700",
+		"  Lodge Cast Iron Skillet – 10.25” Pre-Seasoned with Handle | Lodge | 3 x 15000 | tax -2700 | total -47700",
+	}
+	// The Windows-1252 body is decoded.
+	latin1Order = []string{
+		"2023-01-06 111-6666666-6666666 ship 0 promo 0 tax -1600 total -21600",
+		"  Café Crème Coffee Pods – Pack of 24 | Café Co | 1 x 20000 | tax -1600 | total -21600",
+	}
+	// Free shipping cancels out shipping, so all the tax is the item's.
+	base64Order = []string{
+		"2023-01-04 111-3333333-3333333 ship -4000 promo 4000 tax -960 total -12960",
+		"  Kitchen Towels & Cloths |  | 1 x 12000 | tax -960 | total -12960",
+	}
+)
+
+func TestLoadEmails(t *testing.T) {
+	// A Maildir with messages in cur and new, without suffixes.
+	maildir := t.TempDir()
+	for dir, name := range map[string]string{"cur": "shipped-qp.eml", "new": "shipped-base64.eml"} {
+		b, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Join(maildir, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(maildir, dir, strings.TrimSuffix(name, ".eml")+":2,S"), b, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name, path string
+		want       [][]string
+		diags      []string
+	}{
+		{
+			// An order with only a confirmation is dated by it.
+			name: "plain text", path: "testdata/confirmation.eml",
+			want:  [][]string{confirmationOrder},
+			diags: []string{"shipping_tax 111-1111111-1111111", "unshipped 111-1111111-1111111"},
+		},
+		{
+			name: "quoted-printable multipart", path: "testdata/shipped-qp.eml",
+			want: [][]string{quotedPrintableOrder},
+		},
+		{
+			name: "base64 HTML", path: "testdata/shipped-base64.eml",
+			want: [][]string{base64Order},
+		},
+		{
+			name: "Windows-1252", path: "testdata/shipped-latin1.eml",
+			want: [][]string{latin1Order},
+		},
+		{
+			// The mbox has an email that isn't Amazon's, a duplicate
+			// shipment email, a shipment email with no total, and an email
+			// that isn't valid. Its quoted "From " lines are unescaped.
+			name: "mbox", path: "testdata/mailbox.mbox",
+			want: [][]string{{
+				"2023-01-05 111-4444444-4444444 ship 0 promo 0 tax -720 total -9710",
+				"  From the Earth to the Moon (Paperback) | Amazon.com | 1 x 8990 | tax -720 | total -9710",
+			}},
+			diags: []string{"unparsed 111-5555555-5555555", "unparsed"},
+		},
+		{
+			name: "folder of .eml files", path: "testdata",
+			want:  [][]string{confirmationOrder, quotedPrintableOrder, base64Order, latin1Order},
+			diags: []string{"shipping_tax 111-1111111-1111111", "unshipped 111-1111111-1111111"},
+		},
+		{
+			name: "Maildir", path: maildir,
+			want: [][]string{quotedPrintableOrder, base64Order},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			odm, report, err := LoadEmails(tc.path)
+			if err != nil {
+				t.Fatalf("LoadEmails(%q) = %v", tc.path, err)
+			}
+			var want []string
+			for _, w := range tc.want {
+				want = append(want, w...)
+			}
+			got, diags := summarize(odm, report)
+			if d := diff(got, want); d != "" {
+				t.Errorf("LoadEmails(%q) orders differ, %s", tc.path, d)
+			}
+			if d := diff(diags, tc.diags); d != "" {
+				t.Errorf("LoadEmails(%q) diagnostics differ, %s", tc.path, d)
+			}
+		})
+	}
+}
+
+func TestBodyText(t *testing.T) {
+	for _, tc := range []struct {
+		contentType string
+		body        string
+		want        string
+		ok          bool
+	}{
+		{"text/plain", "Caf\xc3\xa9", "Café", true},
+		{"text/plain; charset=UTF-8", "Caf\xc3\xa9", "Café", true},
+		{"text/plain; charset=us-ascii", "Cafe", "Cafe", true},
+		{"text/plain; charset=ISO-8859-1", "Caf\xe9", "Café", true},
+		{"text/plain; charset=latin1", "Caf\xe9", "Café", true},
+		{"text/plain; charset=\"windows-1252\"", "\x93Caf\xe9\x94", "“Café”", true},
+		{"text/plain; charset=x-unknown", "Caf\xe9", "", false},
+	} {
+		t.Run(tc.contentType, func(t *testing.T) {
+			got, _, err := bodyText(tc.contentType, "", strings.NewReader(tc.body))
+			if (err == nil) != tc.ok {
+				t.Fatalf("bodyText() = %v, want ok %v", err, tc.ok)
+			}
+			if got != tc.want {
+				t.Errorf("bodyText() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadEmailsMissing(t *testing.T) {
+	if _, _, err := LoadEmails("testdata/missing.mbox"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("LoadEmails() = %v, want a not-exist error", err)
+	}
+}
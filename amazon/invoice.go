@@ -0,0 +1,408 @@
+package amazon
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dbinit/ynab-amazon-import/orders"
+)
+
+// InvoiceName is the name of the Amazon invoice source.
+const InvoiceName = "amazon-invoice"
+
+func init() {
+	orders.Register(InvoiceName, InvoiceSource{})
+}
+
+// InvoiceSource is the Amazon invoice source, reading saved printable invoice
+// or order details pages from an HTML file or a folder of them named by
+// Files.Orders.
+type InvoiceSource struct{}
+
+// Retailer returns the Amazon defaults.
+func (InvoiceSource) Retailer() orders.Retailer { return Retailer }
+
+// Load reads the invoices with LoadInvoices.
+func (InvoiceSource) Load(files orders.Files) (map[string]*orders.Order, *orders.MergeReport, error) {
+	if files.Orders == "" {
+		return nil, nil, errors.New("the Amazon invoice source needs an invoice HTML file or folder")
+	}
+	return LoadInvoices(files.Orders)
+}
+
+// LoadInvoices reads saved Amazon invoice pages from an HTML file or a folder
+// of .html and .htm files. Each shipped shipment of an invoice is an order
+// shipment, with the order's shipping, promotions, tax, total and gift card
+// payment shared between the shipments by their item subtotals. If the
+// invoice lists a card charge for each shipment, those are the shipments'
+// card payments, and the rest of each shipment's total its gift card payment.
+// Charges that don't add up with the totals are reported as
+// DiagChargeMismatch diagnostics, and invoices that can't be parsed as
+// DiagUnparsed diagnostics. Only reading the files returns an error.
+func LoadInvoices(name string) (map[string]*orders.Order, *orders.MergeReport, error) {
+	names := []string{name}
+	if fi, err := os.Stat(name); err != nil {
+		return nil, nil, fmt.Errorf("os.Stat(%q): %w", name, err)
+	} else if fi.IsDir() {
+		names = nil
+		for _, pattern := range []string{"*.html", "*.htm"} {
+			m, err := filepath.Glob(filepath.Join(name, pattern))
+			if err != nil {
+				return nil, nil, fmt.Errorf("filepath.Glob(%q): %w", name, err)
+			}
+			names = append(names, m...)
+		}
+		sort.Strings(names)
+	}
+
+	odm := make(map[string]*orders.Order)
+	idm := make(map[string]*orders.Order)
+	var diags []*orders.Diagnostic
+	for _, n := range names {
+		b, err := os.ReadFile(n)
+		if err != nil {
+			return nil, nil, fmt.Errorf("os.ReadFile(%q): %w", n, err)
+		}
+		rs, mismatch, err := parseInvoice(htmlText(string(b)))
+		if err != nil {
+			d := &orders.Diagnostic{Kind: orders.DiagUnparsed, Detail: fmt.Sprintf("%s: %v", n, err)}
+			if len(rs) > 0 {
+				d.OrderID = rs[0].orderID
+			}
+			diags = append(diags, d)
+			continue
+		}
+		if mismatch != "" {
+			diags = append(diags, &orders.Diagnostic{Kind: orders.DiagChargeMismatch, OrderID: rs[0].orderID, Detail: fmt.Sprintf("%s: %s", n, mismatch)})
+		}
+		for _, r := range rs {
+			r.add(odm, idm)
+		}
+	}
+
+	merged, report := orders.Merge(odm, idm)
+	report.Diagnostics = append(report.Diagnostics, diags...)
+	return merged, report, nil
+}
+
+// OverlayInvoices reads saved invoice pages with LoadInvoices and replaces the
+// payments of the matching shipments in odm with the invoices' gift card and
+// card payments, as the order CSVs don't split charges paid partly by gift
+// card. Invoice shipments match by Order.Key, or by order ID if the order has
+// a single shipment in both. Invoice shipments without a match, or paying more
+// by gift card than their order shipment's total, are reported as
+// DiagInvoiceUnmatched diagnostics, along with the invoices' DiagUnparsed and
+// DiagChargeMismatch diagnostics.
+func OverlayInvoices(odm map[string]*orders.Order, report *orders.MergeReport, name string) error {
+	inv, ireport, err := LoadInvoices(name)
+	if err != nil {
+		return err
+	}
+	for _, d := range ireport.Diagnostics {
+		if d.Kind == orders.DiagUnparsed || d.Kind == orders.DiagChargeMismatch {
+			report.Diagnostics = append(report.Diagnostics, d)
+		}
+	}
+
+	byID := func(m map[string]*orders.Order) map[string][]*orders.Order {
+		ids := make(map[string][]*orders.Order)
+		for _, od := range m {
+			ids[od.ID] = append(ids[od.ID], od)
+		}
+		return ids
+	}
+	ids, invIDs := byID(odm), byID(inv)
+	for _, k := range orders.SortedKeys(inv) {
+		in := inv[k]
+		od := odm[k]
+		if od == nil && len(ids[in.ID]) == 1 && len(invIDs[in.ID]) == 1 {
+			od = ids[in.ID][0]
+		}
+		d := &orders.Diagnostic{Kind: orders.DiagInvoiceUnmatched, OrderID: in.ID, ShipmentDate: in.ShipmentDate.String()}
+		if od == nil {
+			d.Detail = "the invoice shipment has no order shipment; its payments were left out"
+			report.Diagnostics = append(report.Diagnostics, d)
+			continue
+		}
+		// Charges are negative, so a larger gift card part is smaller.
+		gift := in.GiftCardTotal()
+		if gift < od.TotalCharged {
+			d.Amount = gift
+			d.Detail = fmt.Sprintf("the invoice's gift card payment of %s is more than the shipment's total of %s; its payments were left out",
+				orders.FormatMoney(gift), orders.FormatMoney(od.TotalCharged))
+			report.Diagnostics = append(report.Diagnostics, d)
+			continue
+		}
+		// The invoice names the card better than the CSVs, which may name
+		// both the gift card and the card.
+		instrument := ""
+		for _, ps := range [][]*orders.Payment{in.Payments, od.Payments} {
+			for _, p := range ps {
+				if !p.GiftCard && instrument == "" {
+					instrument = p.Instrument
+				}
+			}
+		}
+		if instrument == "" {
+			instrument = "Card"
+		}
+		od.Payments = nil
+		if card := od.TotalCharged - gift; card != 0 {
+			od.Payments = append(od.Payments, &orders.Payment{Instrument: instrument, Amount: card})
+		}
+		if gift != 0 {
+			od.Payments = append(od.Payments, &orders.Payment{Instrument: "Gift Card", GiftCard: true, Amount: gift})
+		}
+	}
+	return nil
+}
+
+var (
+	// Matches the start of a shipment, e.g. "Shipped on January 3, 2023".
+	shippedOnRE = regexp.MustCompile(`(?i)^shipped on\s*:?\s*(.*)$`)
+	// Matches the start of a shipment that hasn't shipped.
+	notShippedRE = regexp.MustCompile(`(?i)^(?:not yet shipped|preparing for shipment|shipping now|cancelled)`)
+	// Matches an invoice item, e.g. "2 of: AA Batteries 24 Pack".
+	invoiceItemRE = regexp.MustCompile(`(?i)^(\d+)\s+of\s*:\s*(.+)$`)
+	// Matches the payment method, e.g. "Visa | Last digits: 1234".
+	paymentMethodRE = regexp.MustCompile(`(?i)^(?:payment method\s*:?\s*)?(.+?)\s*\|\s*last digits\s*:?\s*(\d+)$`)
+	// Matches a card charge, e.g. "Visa ending in 1234: January 3, 2023:
+	// $43.09".
+	cardChargeRE = regexp.MustCompile(`(?i)^(.+? ending in \d+)\s*:\s*(.+?)\s*:\s*(\$[\d,]+\.\d{2})$`)
+	// Matches a seller's profile link after the seller name.
+	sellerProfileRE = regexp.MustCompile(`\s*\(seller profile\)$`)
+)
+
+// Invoice date layouts.
+var invoiceDateLayouts = []string{"January 2, 2006", "Jan 2, 2006", "2 January 2006", "January 2 2006"}
+
+// parseInvoiceDate parses an invoice date in the local time zone.
+func parseInvoiceDate(s string) (time.Time, error) {
+	for _, layout := range invoiceDateLayouts {
+		if t, err := time.ParseInLocation(layout, strings.TrimSpace(s), time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("failed to parse %q as a date", s)
+}
+
+// invoiceCharge is a card charge listed on an invoice.
+type invoiceCharge struct {
+	instrument string
+	date       time.Time
+	amount     int64
+}
+
+// parseInvoice reads the text of an invoice into a receipt for each shipped
+// shipment, and explains any card charges it left out because they don't add
+// up with the totals. It returns an error for invoices it can't read, with
+// the receipts so far.
+func parseInvoice(text string) (shipments []*receipt, mismatch string, err error) {
+	ids := orderIDRE.FindAllString(text, -1)
+	if len(ids) == 0 {
+		return nil, "", errors.New("no order ID")
+	}
+	order := &receipt{orderID: ids[0], grocery: grocerySellerRE.MatchString(text), subscribe: subscribeAndSaveRE.MatchString(text)}
+	for _, id := range ids[1:] {
+		if id != order.orderID {
+			return []*receipt{order}, "", fmt.Errorf("the invoice covers more than one order, %s and %s", order.orderID, id)
+		}
+	}
+
+	var (
+		cur     *receipt
+		item    *receiptItem
+		charges []*invoiceCharge
+		haveSum = make(map[int]bool)
+		payment = false
+		pending = ""
+		labels  []int
+	)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(strings.ReplaceAll(line, "\u00a0", " "))
+		if line == "" {
+			continue
+		}
+		// Some dates are on the line after their label.
+		if pending != "" {
+			line, pending = pending+" "+line, ""
+		}
+
+		switch {
+		case shippedOnRE.MatchString(line):
+			v := shippedOnRE.FindStringSubmatch(line)[1]
+			if v == "" {
+				pending = line
+				continue
+			}
+			d, err := parseInvoiceDate(v)
+			if err != nil {
+				return []*receipt{order}, "", err
+			}
+			cur, item, payment = &receipt{orderID: order.orderID, date: d, shipped: true}, nil, false
+			shipments = append(shipments, cur)
+			continue
+		case notShippedRE.MatchString(line):
+			cur, item, payment = nil, nil, false
+			continue
+		case strings.EqualFold(line, "payment information"):
+			cur, item, payment = nil, nil, true
+			continue
+		}
+
+		if cur != nil {
+			switch m := invoiceItemRE.FindStringSubmatch(line); {
+			case m != nil:
+				q, _ := strconv.ParseInt(m[1], 10, 64)
+				item = &receiptItem{title: m[2], quantity: q}
+				cur.items = append(cur.items, item)
+				// The price may follow the title on the same line.
+				if m := amountLineRE.FindStringSubmatch(item.title); m != nil && m[1] != "" {
+					n, err := parseAmount(m[2])
+					if err != nil {
+						return []*receipt{order}, "", err
+					}
+					item.title, item.price = m[1], n
+				}
+			case item != nil && soldByRE.MatchString(line):
+				item.seller = sellerProfileRE.ReplaceAllString(soldByRE.FindStringSubmatch(line)[1], "")
+			case item != nil && item.price == 0 && amountLineRE.MatchString(line):
+				m := amountLineRE.FindStringSubmatch(line)
+				if m[1] != "" {
+					continue
+				}
+				n, err := parseAmount(m[2])
+				if err != nil {
+					return []*receipt{order}, "", err
+				}
+				item.price = n
+			}
+			continue
+		}
+		if !payment {
+			continue
+		}
+
+		if m := paymentMethodRE.FindStringSubmatch(line); m != nil {
+			order.instrument = fmt.Sprintf("%s ending in %s", strings.TrimSpace(m[1]), m[2])
+			continue
+		}
+		if m := cardChargeRE.FindStringSubmatch(line); m != nil {
+			d, err := parseInvoiceDate(m[2])
+			if err != nil {
+				return []*receipt{order}, "", err
+			}
+			n, err := parseAmount(m[3])
+			if err != nil {
+				return []*receipt{order}, "", err
+			}
+			charges = append(charges, &invoiceCharge{instrument: m[1], date: d, amount: n})
+			continue
+		}
+		label, amount := line, ""
+		if m := amountLineRE.FindStringSubmatch(line); m != nil {
+			label, amount = m[1], m[2]
+		}
+		// The payment section has no item titles to mistake for labels.
+		i := summaryLabel(label, false)
+		if amount != "" && label == "" && len(labels) > 0 {
+			// The amounts of a column of labels follow it in order.
+			i, labels = labels[0], labels[1:]
+		}
+		if i < 0 {
+			continue
+		}
+		if amount == "" {
+			labels = append(labels, i)
+			continue
+		}
+		n, err := parseAmount(amount)
+		if err != nil {
+			return []*receipt{order}, "", err
+		}
+		if l := summaryLabels[i].label; l != labelIgnore && !haveSum[l] {
+			haveSum[l] = true
+			*order.amount(l) += n
+		}
+	}
+
+	if !haveSum[labelTotal] {
+		return []*receipt{order}, "", errors.New("no grand total")
+	}
+	// The grand total is what remained to pay after gift cards.
+	order.total += order.giftCard
+	if len(shipments) == 0 {
+		return []*receipt{order}, "", errors.New("no shipped items")
+	}
+	for _, s := range shipments {
+		for _, it := range s.items {
+			if it.price == 0 {
+				return []*receipt{order}, "", fmt.Errorf("no price for %q", it.title)
+			}
+			s.subtotal += it.price * it.quantity
+		}
+	}
+	order.share(shipments)
+
+	// Card charges made as each shipment shipped are the shipments' card
+	// payments, and the rest of each shipment's total was paid by gift card.
+	// The totals stay as shared out, so charges are only used if they add up
+	// with the order total and none is more than its shipment's total.
+	if len(charges) == len(shipments) {
+		sort.SliceStable(charges, func(i, j int) bool { return charges[i].date.Before(charges[j].date) })
+		charged := int64(0)
+		fits := true
+		for i, s := range shipments {
+			charged += charges[i].amount
+			fits = fits && charges[i].amount <= s.total
+		}
+		if charged+order.giftCard != order.total || !fits {
+			return shipments, fmt.Sprintf("card charges of %s and gift cards of %s don't add up with the shipments' totals of %s; using the invoice's payment method",
+				orders.FormatMoney(charged), orders.FormatMoney(order.giftCard), orders.FormatMoney(order.total)), nil
+		}
+		for i, s := range shipments {
+			s.instrument = charges[i].instrument
+			s.giftCard = s.total - charges[i].amount
+		}
+	}
+	return shipments, "", nil
+}
+
+// share shares the order amounts between its shipments by their subtotals.
+// The last shipment gets any rounding remainder.
+func (r *receipt) share(shipments []*receipt) {
+	var subtotal int64
+	for _, s := range shipments {
+		subtotal += s.subtotal
+	}
+	amounts := []func(*receipt) *int64{
+		func(r *receipt) *int64 { return &r.shipping },
+		func(r *receipt) *int64 { return &r.promotions },
+		func(r *receipt) *int64 { return &r.tax },
+		func(r *receipt) *int64 { return &r.total },
+		func(r *receipt) *int64 { return &r.giftCard },
+		func(r *receipt) *int64 { return &r.tip },
+		func(r *receipt) *int64 { return &r.fees },
+	}
+	for _, amount := range amounts {
+		remaining := *amount(r)
+		for i, s := range shipments {
+			n := remaining
+			if i < len(shipments)-1 && subtotal > 0 {
+				n = shareOf(*amount(r), s.subtotal, subtotal)
+			}
+			*amount(s) = n
+			remaining -= n
+		}
+	}
+	for _, s := range shipments {
+		s.instrument, s.grocery, s.subscribe = r.instrument, r.grocery, r.subscribe
+	}
+}
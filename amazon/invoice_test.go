@@ -0,0 +1,227 @@
+package amazon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dbinit/ynab-amazon-import/orders"
+	"github.com/go-openapi/strfmt"
+)
+
+func TestParseInvoice(t *testing.T) {
+	for _, tc := range []struct {
+		name, text string
+		want, err  string
+	}{
+		{
+			// With no card charge per shipment, the card pays what the gift
+			// card doesn't.
+			name: "single shipment",
+			text: "Order #112-0000000-0000001\nShipped on\nJanuary 3, 2023\n1 of: Pens $2.50\n2 of: Ink\nSold by: Ink Co (seller profile)\n$1.25\n" +
+				"Payment information\nPayment Method: Visa | Last digits: 4321\nItem(s) Subtotal: $5.00\nShipping & Handling: $1.00\n" +
+				"Estimated tax to be collected: $0.60\nGift Card Amount: -$2.00\nGrand Total: $4.60",
+			want: "2023-01-03 sub 5000 ship 1000 promo 0 tax 600 total 6600 gift 2000 by Visa ending in 4321: Pens||1|2500; Ink|Ink Co|2|1250",
+		},
+		{
+			// Each shipment keeps its share of the total, and the card
+			// charged for it leaves the rest to the gift card.
+			name: "card charges",
+			text: "Order #112-0000000-0000001\nShipped on January 3, 2023\n1 of: Pens $3.00\nShipped on January 5, 2023\n1 of: Ink $1.00\n" +
+				"Payment information\nPayment Method: Visa | Last digits: 4321\nGift Card Amount: -$2.00\nGrand Total: $2.00\n" +
+				"Visa ending in 4321: January 5, 2023: $1.00\nVisa ending in 4321: January 3, 2023: $1.00",
+			want: "2023-01-03 sub 3000 ship 0 promo 0 tax 0 total 3000 gift 2000 by Visa ending in 4321: Pens||1|3000\n" +
+				"2023-01-05 sub 1000 ship 0 promo 0 tax 0 total 1000 gift 0 by Visa ending in 4321: Ink||1|1000",
+		},
+		{
+			// Charges that don't add up are left out, and the gift card is
+			// shared out like the other amounts.
+			name: "card charge mismatch",
+			text: "Order #112-0000000-0000001\nShipped on January 3, 2023\n1 of: Pens $3.00\nShipped on January 5, 2023\n1 of: Ink $1.00\n" +
+				"Payment information\nPayment Method: Visa | Last digits: 4321\nGift Card Amount: -$2.00\nGrand Total: $2.00\n" +
+				"Visa ending in 4321: January 3, 2023: $0.50\nVisa ending in 4321: January 5, 2023: $1.75",
+			want: "2023-01-03 sub 3000 ship 0 promo 0 tax 0 total 3000 gift 1500 by Visa ending in 4321: Pens||1|3000\n" +
+				"2023-01-05 sub 1000 ship 0 promo 0 tax 0 total 1000 gift 500 by Visa ending in 4321: Ink||1|1000\n" +
+				"mismatch: card charges of $2.25 and gift cards of $2.00 don't add up with the shipments' totals of $4.00; using the invoice's payment method",
+		},
+		{
+			name: "no order ID",
+			text: "Shipped on January 3, 2023\n1 of: Pens $1.00",
+			err:  "no order ID",
+		},
+		{
+			name: "two orders",
+			text: "Order #112-0000000-0000001\nOrder #112-0000000-0000002",
+			err:  "the invoice covers more than one order, 112-0000000-0000001 and 112-0000000-0000002",
+		},
+		{
+			name: "invalid shipment date",
+			text: "Order #112-0000000-0000001\nShipped on Someday\n1 of: Pens $1.00",
+			err:  `failed to parse "Someday" as a date`,
+		},
+		{
+			name: "no grand total",
+			text: "Order #112-0000000-0000001\nShipped on January 3, 2023\n1 of: Pens $1.00\nPayment information\nItem(s) Subtotal: $1.00",
+			err:  "no grand total",
+		},
+		{
+			name: "nothing shipped",
+			text: "Order #112-0000000-0000001\nNot Yet Shipped\n1 of: Pens $1.00\nPayment information\nGrand Total: $1.00",
+			err:  "no shipped items",
+		},
+		{
+			name: "no item price",
+			text: "Order #112-0000000-0000001\nShipped on January 3, 2023\n1 of: Pens\nPayment information\nGrand Total: $1.00",
+			err:  `no price for "Pens"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rs, mismatch, err := parseInvoice(tc.text)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("parseInvoice() = %v, want error %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseInvoice() = %v", err)
+			}
+			var got []string
+			for _, r := range rs {
+				var items []string
+				for _, it := range r.items {
+					items = append(items, fmt.Sprintf("%s|%s|%d|%d", it.title, it.seller, it.quantity, it.price))
+				}
+				got = append(got, fmt.Sprintf("%s sub %d ship %d promo %d tax %d total %d gift %d by %s: %s",
+					r.date.Format("2006-01-02"), r.subtotal, r.shipping, r.promotions, r.tax, r.total, r.giftCard, r.instrument, strings.Join(items, "; ")))
+			}
+			if mismatch != "" {
+				got = append(got, "mismatch: "+mismatch)
+			}
+			if s := strings.Join(got, "\n"); s != tc.want {
+				t.Errorf("parseInvoice() =\n\t%s\nwant\n\t%s", s, tc.want)
+			}
+		})
+	}
+}
+
+// invoiceOrders are the orders of testdata/invoice.html, which has two
+// shipped shipments and one that hasn't shipped. The order's shipping, free
+// shipping, tax and gift card are shared between the shipments by their
+// subtotals, $39.90 and $18.00, and each shipment's card charge is listed.
+var invoiceOrders = []string{
+	"2023-01-03 112-5555555-5555555 ship -4130 promo 4130 tax -3580 total -43480",
+	"  paid -36590 by Visa ending in 1234",
+	"  paid -6890 by Gift Card",
+	"  Lodge Cast Iron Skillet, 10.25 Inch | Lodge Shop | 1 x 19900 | tax -1790 | total -21690",
+	"  AA Batteries 24 Pack | Amazon.com Services LLC | 2 x 10000 | tax -1790 | total -21790",
+	"2023-01-06 112-5555555-5555555 ship -1860 promo 1860 tax -1610 total -19610",
+	"  paid -16500 by Visa ending in 1234",
+	"  paid -3110 by Gift Card",
+	"  Widget |  | 1 x 18000 | tax -1610 | total -19610",
+}
+
+func TestLoadInvoices(t *testing.T) {
+	// A folder with the invoice and one that can't be parsed.
+	dir := t.TempDir()
+	b, err := os.ReadFile("testdata/invoice.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, s := range map[string]string{
+		"invoice.html": string(b),
+		"broken.htm":   "<html><body>Final Details for Order #112-6666666-6666666</body></html>",
+		"notes.txt":    "Order #112-7777777-7777777",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(s), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name, path string
+		diags      []string
+	}{
+		{"file", "testdata/invoice.html", nil},
+		{"folder", dir, []string{"unparsed 112-6666666-6666666"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			odm, report, err := LoadInvoices(tc.path)
+			if err != nil {
+				t.Fatalf("LoadInvoices(%q) = %v", tc.path, err)
+			}
+			got, diags := summarize(odm, report)
+			if d := diff(got, invoiceOrders); d != "" {
+				t.Errorf("LoadInvoices(%q) orders differ, %s", tc.path, d)
+			}
+			if d := diff(diags, tc.diags); d != "" {
+				t.Errorf("LoadInvoices(%q) diagnostics differ, %s", tc.path, d)
+			}
+		})
+	}
+}
+
+func TestOverlayInvoices(t *testing.T) {
+	// The CSVs list the invoice's first shipment without the gift card split,
+	// and date its second shipment a day later.
+	csvOrder := func(day int, total int64) *orders.Order {
+		return &orders.Order{
+			ID:           "112-5555555-5555555",
+			ShipmentDate: strfmt.Date(time.Date(2023, 1, day, 0, 0, 0, 0, time.Local)),
+			TotalCharged: total,
+			Payments:     []*orders.Payment{{Instrument: "Gift Certificate/Card and Visa - 1234", Amount: total}},
+		}
+	}
+	for _, tc := range []struct {
+		name  string
+		odm   []*orders.Order
+		want  []string
+		diags []string
+	}{
+		{
+			"overlay",
+			[]*orders.Order{csvOrder(3, -43480), csvOrder(7, -19610)},
+			[]string{
+				"2023-01-03 112-5555555-5555555 ship 0 promo 0 tax 0 total -43480",
+				"  paid -36590 by Visa ending in 1234",
+				"  paid -6890 by Gift Card",
+				"2023-01-07 112-5555555-5555555 ship 0 promo 0 tax 0 total -19610",
+				"  paid -19610 by Gift Certificate/Card and Visa - 1234",
+			},
+			[]string{"invoice_unmatched 112-5555555-5555555"},
+		},
+		{
+			"gift card over total",
+			[]*orders.Order{csvOrder(3, -5000), csvOrder(6, -19610)},
+			[]string{
+				"2023-01-03 112-5555555-5555555 ship 0 promo 0 tax 0 total -5000",
+				"  paid -5000 by Gift Certificate/Card and Visa - 1234",
+				"2023-01-06 112-5555555-5555555 ship 0 promo 0 tax 0 total -19610",
+				"  paid -16500 by Visa ending in 1234",
+				"  paid -3110 by Gift Card",
+			},
+			[]string{"invoice_unmatched 112-5555555-5555555"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			odm := make(map[string]*orders.Order)
+			for _, od := range tc.odm {
+				odm[od.Key()] = od
+			}
+			report := &orders.MergeReport{}
+			if err := OverlayInvoices(odm, report, "testdata/invoice.html"); err != nil {
+				t.Fatalf("OverlayInvoices() = %v", err)
+			}
+			got, diags := summarize(odm, report)
+			if d := diff(got, tc.want); d != "" {
+				t.Errorf("OverlayInvoices() orders differ, %s", d)
+			}
+			if d := diff(diags, tc.diags); d != "" {
+				t.Errorf("OverlayInvoices() diagnostics differ, %s", d)
+			}
+		})
+	}
+}
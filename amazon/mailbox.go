@@ -0,0 +1,221 @@
+package amazon
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// message is a raw email read from a mailbox, named for diagnostics.
+type message struct {
+	name string
+	raw  []byte
+}
+
+// readMailbox reads the messages of a .eml file, an mbox file, a Maildir
+// folder or a folder of .eml files.
+func readMailbox(name string) ([]*message, error) {
+	fi, err := os.Stat(name)
+	if err != nil {
+		return nil, fmt.Errorf("os.Stat(%q): %w", name, err)
+	}
+	if !fi.IsDir() {
+		b, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("os.ReadFile(%q): %w", name, err)
+		}
+		if bytes.HasPrefix(b, []byte("From ")) {
+			return splitMbox(name, b), nil
+		}
+		return []*message{{name: name, raw: b}}, nil
+	}
+
+	// A Maildir keeps messages in its cur and new folders, without suffixes.
+	var files []string
+	for _, sub := range []string{"cur", "new"} {
+		entries, err := os.ReadDir(filepath.Join(name, sub))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("os.ReadDir(%q): %w", filepath.Join(name, sub), err)
+		}
+		for _, e := range entries {
+			if !e.IsDir() {
+				files = append(files, filepath.Join(name, sub, e.Name()))
+			}
+		}
+	}
+	if len(files) == 0 {
+		if files, err = filepath.Glob(filepath.Join(name, "*.eml")); err != nil {
+			return nil, fmt.Errorf("filepath.Glob(%q): %w", name, err)
+		}
+	}
+	sort.Strings(files)
+	var msgs []*message
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("os.ReadFile(%q): %w", f, err)
+		}
+		msgs = append(msgs, &message{name: f, raw: b})
+	}
+	return msgs, nil
+}
+
+// Matches an mboxrd escaped "From " line.
+var fromEscapeRE = regexp.MustCompile(`^>+From `)
+
+// splitMbox splits an mbox file into its messages, which each start with a
+// "From " line, unescaping quoted "From " lines.
+func splitMbox(name string, b []byte) []*message {
+	var msgs []*message
+	var cur *bytes.Buffer
+	s := bufio.NewScanner(bytes.NewReader(b))
+	s.Buffer(make([]byte, 64*1024), len(b)+1)
+	for s.Scan() {
+		line := s.Text()
+		if strings.HasPrefix(line, "From ") {
+			cur = &bytes.Buffer{}
+			msgs = append(msgs, &message{name: fmt.Sprintf("%s message %d", name, len(msgs)+1)})
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		if fromEscapeRE.MatchString(line) {
+			line = line[1:]
+		}
+		cur.WriteString(line)
+		cur.WriteString("\n")
+		msgs[len(msgs)-1].raw = cur.Bytes()
+	}
+	return msgs
+}
+
+// email is a parsed email. HTML bodies are reduced to their text.
+type email struct {
+	messageID string
+	from      string
+	subject   string
+	date      time.Time
+	text      string
+}
+
+// parseEmail parses a raw email, preferring a plain text body to an HTML one.
+func parseEmail(raw []byte) (*email, error) {
+	m, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("mail.ReadMessage(): %w", err)
+	}
+	e := &email{
+		messageID: m.Header.Get("Message-Id"),
+		from:      m.Header.Get("From"),
+		subject:   m.Header.Get("Subject"),
+	}
+	if s, err := new(mime.WordDecoder).DecodeHeader(e.subject); err == nil {
+		e.subject = s
+	}
+	if e.date, err = m.Header.Date(); err != nil {
+		return nil, fmt.Errorf("invalid Date header: %w", err)
+	}
+	plain, htm, err := bodyText(m.Header.Get("Content-Type"), m.Header.Get("Content-Transfer-Encoding"), m.Body)
+	if err != nil {
+		return nil, err
+	}
+	e.text = plain
+	if e.text == "" {
+		e.text = htmlText(htm)
+	}
+	return e, nil
+}
+
+// bodyText returns the first plain text and HTML parts of a body, decoding
+// multipart bodies, transfer encodings and charsets, e.g. ISO-8859-1 or
+// Windows-1252. Parts in charsets it doesn't know are an error.
+func bodyText(contentType, encoding string, body io.Reader) (plain, htm string, err error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		// Messages without a content type are plain text.
+		mediaType = "text/plain"
+	}
+	if strings.HasPrefix(mediaType, "multipart/") {
+		r := multipart.NewReader(body, params["boundary"])
+		for {
+			p, err := r.NextPart()
+			if errors.Is(err, io.EOF) {
+				return plain, htm, nil
+			}
+			if err != nil {
+				return "", "", fmt.Errorf("(*multipart.Reader).NextPart(): %w", err)
+			}
+			pp, ph, err := bodyText(p.Header.Get("Content-Type"), p.Header.Get("Content-Transfer-Encoding"), p)
+			if err != nil {
+				return "", "", err
+			}
+			if plain == "" {
+				plain = pp
+			}
+			if htm == "" {
+				htm = ph
+			}
+		}
+	}
+	if mediaType != "text/plain" && mediaType != "text/html" {
+		return "", "", nil
+	}
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+	if cs := strings.ToLower(strings.TrimSpace(params["charset"])); cs != "" && cs != "utf-8" && cs != "us-ascii" {
+		enc, err := htmlindex.Get(cs)
+		if err != nil {
+			return "", "", fmt.Errorf("unsupported charset %q: %w", params["charset"], err)
+		}
+		body = enc.NewDecoder().Reader(body)
+	}
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to decode %s body: %w", encoding, err)
+	}
+	if mediaType == "text/html" {
+		return "", string(b), nil
+	}
+	return string(b), "", nil
+}
+
+var (
+	// Matches elements whose content isn't text.
+	htmlSkipRE = regexp.MustCompile(`(?is)<(style|script|head)\b.*?</(style|script|head)>`)
+	// Matches tags that end a line of text.
+	htmlBlockRE = regexp.MustCompile(`(?i)<(br|/p|/div|/tr|/td|/th|/li|/h[1-6]|/table)\b[^>]*>`)
+	// Matches any other tag or comment.
+	htmlTagRE = regexp.MustCompile(`(?s)<!--.*?-->|<[^>]*>`)
+)
+
+// htmlText reduces HTML to its text, a line for each block.
+func htmlText(s string) string {
+	s = htmlSkipRE.ReplaceAllString(s, "")
+	s = htmlBlockRE.ReplaceAllString(s, "\n")
+	s = htmlTagRE.ReplaceAllString(s, "")
+	return html.UnescapeString(s)
+}
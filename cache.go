@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/dbinit/ynab-amazon-import/ynabsync"
+)
+
+// defaultCacheDir returns the default cache directory.
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "cache"
+	}
+	return filepath.Join(dir, "ynab-amazon-import")
+}
+
+// cacheFlags registers the metadata cache flags.
+func (o *options) cacheFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.cacheDir, "cache", defaultCacheDir(), "Directory caching budget metadata, or empty to disable it")
+	fs.BoolVar(&o.refresh, "refresh", false, "Ignore cached budget metadata and fetch it in full")
+}
+
+// metaCache returns the metadata cache.
+func (o *options) metaCache() *ynabsync.Cache {
+	if o.cache == nil {
+		o.cache = &ynabsync.Cache{Dir: o.cacheDir, Refresh: o.refresh, Client: o.ynabClient(), Logf: log.Printf}
+	}
+	return o.cache
+}
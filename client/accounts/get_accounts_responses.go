@@ -0,0 +1,256 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package accounts
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/dbinit/ynab-amazon-import/models"
+)
+
+// GetAccountsReader is a Reader for the GetAccounts structure.
+type GetAccountsReader struct {
+	formats strfmt.Registry
+}
+
+// ReadResponse reads a server response into the received o.
+func (o *GetAccountsReader) ReadResponse(response runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	switch response.Code() {
+	case 200:
+		result := NewGetAccountsOK()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return result, nil
+	case 404:
+		result := NewGetAccountsNotFound()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+	default:
+		result := NewGetAccountsDefault(response.Code())
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		if response.Code()/100 == 2 {
+			return result, nil
+		}
+		return nil, result
+	}
+}
+
+// NewGetAccountsOK creates a GetAccountsOK with default headers values
+func NewGetAccountsOK() *GetAccountsOK {
+	return &GetAccountsOK{}
+}
+
+/*
+GetAccountsOK describes a response with status code 200, with default header values.
+
+The list of requested accounts
+*/
+type GetAccountsOK struct {
+	Payload *models.AccountsResponse
+}
+
+// IsSuccess returns true when this get accounts Ok response has a 2xx status code
+func (o *GetAccountsOK) IsSuccess() bool {
+	return true
+}
+
+// IsRedirect returns true when this get accounts Ok response has a 3xx status code
+func (o *GetAccountsOK) IsRedirect() bool {
+	return false
+}
+
+// IsClientError returns true when this get accounts Ok response has a 4xx status code
+func (o *GetAccountsOK) IsClientError() bool {
+	return false
+}
+
+// IsServerError returns true when this get accounts Ok response has a 5xx status code
+func (o *GetAccountsOK) IsServerError() bool {
+	return false
+}
+
+// IsCode returns true when this get accounts Ok response a status code equal to that given
+func (o *GetAccountsOK) IsCode(code int) bool {
+	return code == 200
+}
+
+// Code gets the status code for the get accounts Ok response
+func (o *GetAccountsOK) Code() int {
+	return 200
+}
+
+func (o *GetAccountsOK) Error() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/accounts][%d] getAccountsOk  %+v", 200, o.Payload)
+}
+
+func (o *GetAccountsOK) String() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/accounts][%d] getAccountsOk  %+v", 200, o.Payload)
+}
+
+func (o *GetAccountsOK) GetPayload() *models.AccountsResponse {
+	return o.Payload
+}
+
+func (o *GetAccountsOK) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	o.Payload = new(models.AccountsResponse)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewGetAccountsNotFound creates a GetAccountsNotFound with default headers values
+func NewGetAccountsNotFound() *GetAccountsNotFound {
+	return &GetAccountsNotFound{}
+}
+
+/*
+GetAccountsNotFound describes a response with status code 404, with default header values.
+
+No accounts were found
+*/
+type GetAccountsNotFound struct {
+	Payload *models.ErrorResponse
+}
+
+// IsSuccess returns true when this get accounts not found response has a 2xx status code
+func (o *GetAccountsNotFound) IsSuccess() bool {
+	return false
+}
+
+// IsRedirect returns true when this get accounts not found response has a 3xx status code
+func (o *GetAccountsNotFound) IsRedirect() bool {
+	return false
+}
+
+// IsClientError returns true when this get accounts not found response has a 4xx status code
+func (o *GetAccountsNotFound) IsClientError() bool {
+	return true
+}
+
+// IsServerError returns true when this get accounts not found response has a 5xx status code
+func (o *GetAccountsNotFound) IsServerError() bool {
+	return false
+}
+
+// IsCode returns true when this get accounts not found response a status code equal to that given
+func (o *GetAccountsNotFound) IsCode(code int) bool {
+	return code == 404
+}
+
+// Code gets the status code for the get accounts not found response
+func (o *GetAccountsNotFound) Code() int {
+	return 404
+}
+
+func (o *GetAccountsNotFound) Error() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/accounts][%d] getAccountsNotFound  %+v", 404, o.Payload)
+}
+
+func (o *GetAccountsNotFound) String() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/accounts][%d] getAccountsNotFound  %+v", 404, o.Payload)
+}
+
+func (o *GetAccountsNotFound) GetPayload() *models.ErrorResponse {
+	return o.Payload
+}
+
+func (o *GetAccountsNotFound) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	o.Payload = new(models.ErrorResponse)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewGetAccountsDefault creates a GetAccountsDefault with default headers values
+func NewGetAccountsDefault(code int) *GetAccountsDefault {
+	return &GetAccountsDefault{
+		_statusCode: code,
+	}
+}
+
+/*
+GetAccountsDefault describes a response with status code -1, with default header values.
+
+An error occurred
+*/
+type GetAccountsDefault struct {
+	_statusCode int
+
+	Payload *models.ErrorResponse
+}
+
+// IsSuccess returns true when this get accounts default response has a 2xx status code
+func (o *GetAccountsDefault) IsSuccess() bool {
+	return o._statusCode/100 == 2
+}
+
+// IsRedirect returns true when this get accounts default response has a 3xx status code
+func (o *GetAccountsDefault) IsRedirect() bool {
+	return o._statusCode/100 == 3
+}
+
+// IsClientError returns true when this get accounts default response has a 4xx status code
+func (o *GetAccountsDefault) IsClientError() bool {
+	return o._statusCode/100 == 4
+}
+
+// IsServerError returns true when this get accounts default response has a 5xx status code
+func (o *GetAccountsDefault) IsServerError() bool {
+	return o._statusCode/100 == 5
+}
+
+// IsCode returns true when this get accounts default response a status code equal to that given
+func (o *GetAccountsDefault) IsCode(code int) bool {
+	return o._statusCode == code
+}
+
+// Code gets the status code for the get accounts default response
+func (o *GetAccountsDefault) Code() int {
+	return o._statusCode
+}
+
+func (o *GetAccountsDefault) Error() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/accounts][%d] getAccounts default  %+v", o._statusCode, o.Payload)
+}
+
+func (o *GetAccountsDefault) String() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/accounts][%d] getAccounts default  %+v", o._statusCode, o.Payload)
+}
+
+func (o *GetAccountsDefault) GetPayload() *models.ErrorResponse {
+	return o.Payload
+}
+
+func (o *GetAccountsDefault) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	o.Payload = new(models.ErrorResponse)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package categories
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+)
+
+// New creates a new categories API client.
+func New(transport runtime.ClientTransport, formats strfmt.Registry) ClientService {
+	return &Client{transport: transport, formats: formats}
+}
+
+/*
+Client for categories API
+*/
+type Client struct {
+	transport runtime.ClientTransport
+	formats   strfmt.Registry
+}
+
+// ClientOption is the option for Client methods
+type ClientOption func(*runtime.ClientOperation)
+
+// ClientService is the interface for Client methods
+type ClientService interface {
+	GetCategories(params *GetCategoriesParams, authInfo runtime.ClientAuthInfoWriter, opts ...ClientOption) (*GetCategoriesOK, error)
+
+	SetTransport(transport runtime.ClientTransport)
+}
+
+/*
+GetCategories lists categories
+
+Returns all categories grouped by category group.  Amounts (budgeted, activity, balance, etc.) are specific to the current budget month (UTC).
+*/
+func (a *Client) GetCategories(params *GetCategoriesParams, authInfo runtime.ClientAuthInfoWriter, opts ...ClientOption) (*GetCategoriesOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetCategoriesParams()
+	}
+	op := &runtime.ClientOperation{
+		ID:                 "getCategories",
+		Method:             "GET",
+		PathPattern:        "/budgets/{budget_id}/categories",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"https"},
+		Params:             params,
+		Reader:             &GetCategoriesReader{formats: a.formats},
+		AuthInfo:           authInfo,
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	}
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	result, err := a.transport.Submit(op)
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*GetCategoriesOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	unexpectedSuccess := result.(*GetCategoriesDefault)
+	return nil, runtime.NewAPIError("unexpected success response: content available as default response in error", unexpectedSuccess, unexpectedSuccess.Code())
+}
+
+// SetTransport changes the transport on the client
+func (a *Client) SetTransport(transport runtime.ClientTransport) {
+	a.transport = transport
+}
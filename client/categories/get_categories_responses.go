@@ -0,0 +1,256 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package categories
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/dbinit/ynab-amazon-import/models"
+)
+
+// GetCategoriesReader is a Reader for the GetCategories structure.
+type GetCategoriesReader struct {
+	formats strfmt.Registry
+}
+
+// ReadResponse reads a server response into the received o.
+func (o *GetCategoriesReader) ReadResponse(response runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	switch response.Code() {
+	case 200:
+		result := NewGetCategoriesOK()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return result, nil
+	case 404:
+		result := NewGetCategoriesNotFound()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+	default:
+		result := NewGetCategoriesDefault(response.Code())
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		if response.Code()/100 == 2 {
+			return result, nil
+		}
+		return nil, result
+	}
+}
+
+// NewGetCategoriesOK creates a GetCategoriesOK with default headers values
+func NewGetCategoriesOK() *GetCategoriesOK {
+	return &GetCategoriesOK{}
+}
+
+/*
+GetCategoriesOK describes a response with status code 200, with default header values.
+
+The categories grouped by category group
+*/
+type GetCategoriesOK struct {
+	Payload *models.CategoriesResponse
+}
+
+// IsSuccess returns true when this get categories Ok response has a 2xx status code
+func (o *GetCategoriesOK) IsSuccess() bool {
+	return true
+}
+
+// IsRedirect returns true when this get categories Ok response has a 3xx status code
+func (o *GetCategoriesOK) IsRedirect() bool {
+	return false
+}
+
+// IsClientError returns true when this get categories Ok response has a 4xx status code
+func (o *GetCategoriesOK) IsClientError() bool {
+	return false
+}
+
+// IsServerError returns true when this get categories Ok response has a 5xx status code
+func (o *GetCategoriesOK) IsServerError() bool {
+	return false
+}
+
+// IsCode returns true when this get categories Ok response a status code equal to that given
+func (o *GetCategoriesOK) IsCode(code int) bool {
+	return code == 200
+}
+
+// Code gets the status code for the get categories Ok response
+func (o *GetCategoriesOK) Code() int {
+	return 200
+}
+
+func (o *GetCategoriesOK) Error() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/categories][%d] getCategoriesOk  %+v", 200, o.Payload)
+}
+
+func (o *GetCategoriesOK) String() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/categories][%d] getCategoriesOk  %+v", 200, o.Payload)
+}
+
+func (o *GetCategoriesOK) GetPayload() *models.CategoriesResponse {
+	return o.Payload
+}
+
+func (o *GetCategoriesOK) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	o.Payload = new(models.CategoriesResponse)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewGetCategoriesNotFound creates a GetCategoriesNotFound with default headers values
+func NewGetCategoriesNotFound() *GetCategoriesNotFound {
+	return &GetCategoriesNotFound{}
+}
+
+/*
+GetCategoriesNotFound describes a response with status code 404, with default header values.
+
+No categories were found
+*/
+type GetCategoriesNotFound struct {
+	Payload *models.ErrorResponse
+}
+
+// IsSuccess returns true when this get categories not found response has a 2xx status code
+func (o *GetCategoriesNotFound) IsSuccess() bool {
+	return false
+}
+
+// IsRedirect returns true when this get categories not found response has a 3xx status code
+func (o *GetCategoriesNotFound) IsRedirect() bool {
+	return false
+}
+
+// IsClientError returns true when this get categories not found response has a 4xx status code
+func (o *GetCategoriesNotFound) IsClientError() bool {
+	return true
+}
+
+// IsServerError returns true when this get categories not found response has a 5xx status code
+func (o *GetCategoriesNotFound) IsServerError() bool {
+	return false
+}
+
+// IsCode returns true when this get categories not found response a status code equal to that given
+func (o *GetCategoriesNotFound) IsCode(code int) bool {
+	return code == 404
+}
+
+// Code gets the status code for the get categories not found response
+func (o *GetCategoriesNotFound) Code() int {
+	return 404
+}
+
+func (o *GetCategoriesNotFound) Error() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/categories][%d] getCategoriesNotFound  %+v", 404, o.Payload)
+}
+
+func (o *GetCategoriesNotFound) String() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/categories][%d] getCategoriesNotFound  %+v", 404, o.Payload)
+}
+
+func (o *GetCategoriesNotFound) GetPayload() *models.ErrorResponse {
+	return o.Payload
+}
+
+func (o *GetCategoriesNotFound) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	o.Payload = new(models.ErrorResponse)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewGetCategoriesDefault creates a GetCategoriesDefault with default headers values
+func NewGetCategoriesDefault(code int) *GetCategoriesDefault {
+	return &GetCategoriesDefault{
+		_statusCode: code,
+	}
+}
+
+/*
+GetCategoriesDefault describes a response with status code -1, with default header values.
+
+An error occurred
+*/
+type GetCategoriesDefault struct {
+	_statusCode int
+
+	Payload *models.ErrorResponse
+}
+
+// IsSuccess returns true when this get categories default response has a 2xx status code
+func (o *GetCategoriesDefault) IsSuccess() bool {
+	return o._statusCode/100 == 2
+}
+
+// IsRedirect returns true when this get categories default response has a 3xx status code
+func (o *GetCategoriesDefault) IsRedirect() bool {
+	return o._statusCode/100 == 3
+}
+
+// IsClientError returns true when this get categories default response has a 4xx status code
+func (o *GetCategoriesDefault) IsClientError() bool {
+	return o._statusCode/100 == 4
+}
+
+// IsServerError returns true when this get categories default response has a 5xx status code
+func (o *GetCategoriesDefault) IsServerError() bool {
+	return o._statusCode/100 == 5
+}
+
+// IsCode returns true when this get categories default response a status code equal to that given
+func (o *GetCategoriesDefault) IsCode(code int) bool {
+	return o._statusCode == code
+}
+
+// Code gets the status code for the get categories default response
+func (o *GetCategoriesDefault) Code() int {
+	return o._statusCode
+}
+
+func (o *GetCategoriesDefault) Error() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/categories][%d] getCategories default  %+v", o._statusCode, o.Payload)
+}
+
+func (o *GetCategoriesDefault) String() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/categories][%d] getCategories default  %+v", o._statusCode, o.Payload)
+}
+
+func (o *GetCategoriesDefault) GetPayload() *models.ErrorResponse {
+	return o.Payload
+}
+
+func (o *GetCategoriesDefault) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	o.Payload = new(models.ErrorResponse)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package payees
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	cr "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+)
+
+// NewGetPayeesParams creates a new GetPayeesParams object,
+// with the default timeout for this client.
+//
+// Default values are not hydrated, since defaults are normally applied by the API server side.
+//
+// To enforce default values in parameter, use SetDefaults or WithDefaults.
+func NewGetPayeesParams() *GetPayeesParams {
+	return &GetPayeesParams{
+		timeout: cr.DefaultTimeout,
+	}
+}
+
+// NewGetPayeesParamsWithTimeout creates a new GetPayeesParams object
+// with the ability to set a timeout on a request.
+func NewGetPayeesParamsWithTimeout(timeout time.Duration) *GetPayeesParams {
+	return &GetPayeesParams{
+		timeout: timeout,
+	}
+}
+
+// NewGetPayeesParamsWithContext creates a new GetPayeesParams object
+// with the ability to set a context for a request.
+func NewGetPayeesParamsWithContext(ctx context.Context) *GetPayeesParams {
+	return &GetPayeesParams{
+		Context: ctx,
+	}
+}
+
+// NewGetPayeesParamsWithHTTPClient creates a new GetPayeesParams object
+// with the ability to set a custom HTTPClient for a request.
+func NewGetPayeesParamsWithHTTPClient(client *http.Client) *GetPayeesParams {
+	return &GetPayeesParams{
+		HTTPClient: client,
+	}
+}
+
+/*
+GetPayeesParams contains all the parameters to send to the API endpoint
+
+	for the get payees operation.
+
+	Typically these are written to a http.Request.
+*/
+type GetPayeesParams struct {
+
+	/* BudgetID.
+
+	   The id of the budget. "last-used" can be used to specify the last used budget and "default" can be used if default budget selection is enabled (see: https://api.youneedabudget.com/#oauth-default-budget).
+	*/
+	BudgetID string
+
+	/* LastKnowledgeOfServer.
+
+	   The starting server knowledge.  If provided, only entities that have changed since `last_knowledge_of_server` will be included.
+
+	   Format: int64
+	*/
+	LastKnowledgeOfServer *int64
+
+	timeout    time.Duration
+	Context    context.Context
+	HTTPClient *http.Client
+}
+
+// WithDefaults hydrates default values in the get payees params (not the query body).
+//
+// All values with no default are reset to their zero value.
+func (o *GetPayeesParams) WithDefaults() *GetPayeesParams {
+	o.SetDefaults()
+	return o
+}
+
+// SetDefaults hydrates default values in the get payees params (not the query body).
+//
+// All values with no default are reset to their zero value.
+func (o *GetPayeesParams) SetDefaults() {
+	// no default values defined for this parameter
+}
+
+// WithTimeout adds the timeout to the get payees params
+func (o *GetPayeesParams) WithTimeout(timeout time.Duration) *GetPayeesParams {
+	o.SetTimeout(timeout)
+	return o
+}
+
+// SetTimeout adds the timeout to the get payees params
+func (o *GetPayeesParams) SetTimeout(timeout time.Duration) {
+	o.timeout = timeout
+}
+
+// WithContext adds the context to the get payees params
+func (o *GetPayeesParams) WithContext(ctx context.Context) *GetPayeesParams {
+	o.SetContext(ctx)
+	return o
+}
+
+// SetContext adds the context to the get payees params
+func (o *GetPayeesParams) SetContext(ctx context.Context) {
+	o.Context = ctx
+}
+
+// WithHTTPClient adds the HTTPClient to the get payees params
+func (o *GetPayeesParams) WithHTTPClient(client *http.Client) *GetPayeesParams {
+	o.SetHTTPClient(client)
+	return o
+}
+
+// SetHTTPClient adds the HTTPClient to the get payees params
+func (o *GetPayeesParams) SetHTTPClient(client *http.Client) {
+	o.HTTPClient = client
+}
+
+// WithBudgetID adds the budgetID to the get payees params
+func (o *GetPayeesParams) WithBudgetID(budgetID string) *GetPayeesParams {
+	o.SetBudgetID(budgetID)
+	return o
+}
+
+// SetBudgetID adds the budgetId to the get payees params
+func (o *GetPayeesParams) SetBudgetID(budgetID string) {
+	o.BudgetID = budgetID
+}
+
+// WithLastKnowledgeOfServer adds the lastKnowledgeOfServer to the get payees params
+func (o *GetPayeesParams) WithLastKnowledgeOfServer(lastKnowledgeOfServer *int64) *GetPayeesParams {
+	o.SetLastKnowledgeOfServer(lastKnowledgeOfServer)
+	return o
+}
+
+// SetLastKnowledgeOfServer adds the lastKnowledgeOfServer to the get payees params
+func (o *GetPayeesParams) SetLastKnowledgeOfServer(lastKnowledgeOfServer *int64) {
+	o.LastKnowledgeOfServer = lastKnowledgeOfServer
+}
+
+// WriteToRequest writes these params to a swagger request
+func (o *GetPayeesParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
+
+	if err := r.SetTimeout(o.timeout); err != nil {
+		return err
+	}
+	var res []error
+
+	// path param budget_id
+	if err := r.SetPathParam("budget_id", o.BudgetID); err != nil {
+		return err
+	}
+
+	if o.LastKnowledgeOfServer != nil {
+
+		// query param last_knowledge_of_server
+		var qrLastKnowledgeOfServer int64
+
+		if o.LastKnowledgeOfServer != nil {
+			qrLastKnowledgeOfServer = *o.LastKnowledgeOfServer
+		}
+		qLastKnowledgeOfServer := swag.FormatInt64(qrLastKnowledgeOfServer)
+		if qLastKnowledgeOfServer != "" {
+
+			if err := r.SetQueryParam("last_knowledge_of_server", qLastKnowledgeOfServer); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
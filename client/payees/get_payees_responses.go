@@ -0,0 +1,256 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package payees
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/dbinit/ynab-amazon-import/models"
+)
+
+// GetPayeesReader is a Reader for the GetPayees structure.
+type GetPayeesReader struct {
+	formats strfmt.Registry
+}
+
+// ReadResponse reads a server response into the received o.
+func (o *GetPayeesReader) ReadResponse(response runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	switch response.Code() {
+	case 200:
+		result := NewGetPayeesOK()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return result, nil
+	case 404:
+		result := NewGetPayeesNotFound()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+	default:
+		result := NewGetPayeesDefault(response.Code())
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		if response.Code()/100 == 2 {
+			return result, nil
+		}
+		return nil, result
+	}
+}
+
+// NewGetPayeesOK creates a GetPayeesOK with default headers values
+func NewGetPayeesOK() *GetPayeesOK {
+	return &GetPayeesOK{}
+}
+
+/*
+GetPayeesOK describes a response with status code 200, with default header values.
+
+The requested list of payees
+*/
+type GetPayeesOK struct {
+	Payload *models.PayeesResponse
+}
+
+// IsSuccess returns true when this get payees Ok response has a 2xx status code
+func (o *GetPayeesOK) IsSuccess() bool {
+	return true
+}
+
+// IsRedirect returns true when this get payees Ok response has a 3xx status code
+func (o *GetPayeesOK) IsRedirect() bool {
+	return false
+}
+
+// IsClientError returns true when this get payees Ok response has a 4xx status code
+func (o *GetPayeesOK) IsClientError() bool {
+	return false
+}
+
+// IsServerError returns true when this get payees Ok response has a 5xx status code
+func (o *GetPayeesOK) IsServerError() bool {
+	return false
+}
+
+// IsCode returns true when this get payees Ok response a status code equal to that given
+func (o *GetPayeesOK) IsCode(code int) bool {
+	return code == 200
+}
+
+// Code gets the status code for the get payees Ok response
+func (o *GetPayeesOK) Code() int {
+	return 200
+}
+
+func (o *GetPayeesOK) Error() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/payees][%d] getPayeesOk  %+v", 200, o.Payload)
+}
+
+func (o *GetPayeesOK) String() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/payees][%d] getPayeesOk  %+v", 200, o.Payload)
+}
+
+func (o *GetPayeesOK) GetPayload() *models.PayeesResponse {
+	return o.Payload
+}
+
+func (o *GetPayeesOK) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	o.Payload = new(models.PayeesResponse)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewGetPayeesNotFound creates a GetPayeesNotFound with default headers values
+func NewGetPayeesNotFound() *GetPayeesNotFound {
+	return &GetPayeesNotFound{}
+}
+
+/*
+GetPayeesNotFound describes a response with status code 404, with default header values.
+
+No payees were found
+*/
+type GetPayeesNotFound struct {
+	Payload *models.ErrorResponse
+}
+
+// IsSuccess returns true when this get payees not found response has a 2xx status code
+func (o *GetPayeesNotFound) IsSuccess() bool {
+	return false
+}
+
+// IsRedirect returns true when this get payees not found response has a 3xx status code
+func (o *GetPayeesNotFound) IsRedirect() bool {
+	return false
+}
+
+// IsClientError returns true when this get payees not found response has a 4xx status code
+func (o *GetPayeesNotFound) IsClientError() bool {
+	return true
+}
+
+// IsServerError returns true when this get payees not found response has a 5xx status code
+func (o *GetPayeesNotFound) IsServerError() bool {
+	return false
+}
+
+// IsCode returns true when this get payees not found response a status code equal to that given
+func (o *GetPayeesNotFound) IsCode(code int) bool {
+	return code == 404
+}
+
+// Code gets the status code for the get payees not found response
+func (o *GetPayeesNotFound) Code() int {
+	return 404
+}
+
+func (o *GetPayeesNotFound) Error() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/payees][%d] getPayeesNotFound  %+v", 404, o.Payload)
+}
+
+func (o *GetPayeesNotFound) String() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/payees][%d] getPayeesNotFound  %+v", 404, o.Payload)
+}
+
+func (o *GetPayeesNotFound) GetPayload() *models.ErrorResponse {
+	return o.Payload
+}
+
+func (o *GetPayeesNotFound) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	o.Payload = new(models.ErrorResponse)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewGetPayeesDefault creates a GetPayeesDefault with default headers values
+func NewGetPayeesDefault(code int) *GetPayeesDefault {
+	return &GetPayeesDefault{
+		_statusCode: code,
+	}
+}
+
+/*
+GetPayeesDefault describes a response with status code -1, with default header values.
+
+An error occurred
+*/
+type GetPayeesDefault struct {
+	_statusCode int
+
+	Payload *models.ErrorResponse
+}
+
+// IsSuccess returns true when this get payees default response has a 2xx status code
+func (o *GetPayeesDefault) IsSuccess() bool {
+	return o._statusCode/100 == 2
+}
+
+// IsRedirect returns true when this get payees default response has a 3xx status code
+func (o *GetPayeesDefault) IsRedirect() bool {
+	return o._statusCode/100 == 3
+}
+
+// IsClientError returns true when this get payees default response has a 4xx status code
+func (o *GetPayeesDefault) IsClientError() bool {
+	return o._statusCode/100 == 4
+}
+
+// IsServerError returns true when this get payees default response has a 5xx status code
+func (o *GetPayeesDefault) IsServerError() bool {
+	return o._statusCode/100 == 5
+}
+
+// IsCode returns true when this get payees default response a status code equal to that given
+func (o *GetPayeesDefault) IsCode(code int) bool {
+	return o._statusCode == code
+}
+
+// Code gets the status code for the get payees default response
+func (o *GetPayeesDefault) Code() int {
+	return o._statusCode
+}
+
+func (o *GetPayeesDefault) Error() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/payees][%d] getPayees default  %+v", o._statusCode, o.Payload)
+}
+
+func (o *GetPayeesDefault) String() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/payees][%d] getPayees default  %+v", o._statusCode, o.Payload)
+}
+
+func (o *GetPayeesDefault) GetPayload() *models.ErrorResponse {
+	return o.Payload
+}
+
+func (o *GetPayeesDefault) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	o.Payload = new(models.ErrorResponse)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package payees
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+)
+
+// New creates a new payees API client.
+func New(transport runtime.ClientTransport, formats strfmt.Registry) ClientService {
+	return &Client{transport: transport, formats: formats}
+}
+
+/*
+Client for payees API
+*/
+type Client struct {
+	transport runtime.ClientTransport
+	formats   strfmt.Registry
+}
+
+// ClientOption is the option for Client methods
+type ClientOption func(*runtime.ClientOperation)
+
+// ClientService is the interface for Client methods
+type ClientService interface {
+	GetPayees(params *GetPayeesParams, authInfo runtime.ClientAuthInfoWriter, opts ...ClientOption) (*GetPayeesOK, error)
+
+	SetTransport(transport runtime.ClientTransport)
+}
+
+/*
+GetPayees lists payees
+
+Returns all payees
+*/
+func (a *Client) GetPayees(params *GetPayeesParams, authInfo runtime.ClientAuthInfoWriter, opts ...ClientOption) (*GetPayeesOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetPayeesParams()
+	}
+	op := &runtime.ClientOperation{
+		ID:                 "getPayees",
+		Method:             "GET",
+		PathPattern:        "/budgets/{budget_id}/payees",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"https"},
+		Params:             params,
+		Reader:             &GetPayeesReader{formats: a.formats},
+		AuthInfo:           authInfo,
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	}
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	result, err := a.transport.Submit(op)
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*GetPayeesOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	unexpectedSuccess := result.(*GetPayeesDefault)
+	return nil, runtime.NewAPIError("unexpected success response: content available as default response in error", unexpectedSuccess, unexpectedSuccess.Code())
+}
+
+// SetTransport changes the transport on the client
+func (a *Client) SetTransport(transport runtime.ClientTransport) {
+	a.transport = transport
+}
@@ -0,0 +1,173 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package transactions
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	cr "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+)
+
+// NewDeleteTransactionParams creates a new DeleteTransactionParams object,
+// with the default timeout for this client.
+//
+// Default values are not hydrated, since defaults are normally applied by the API server side.
+//
+// To enforce default values in parameter, use SetDefaults or WithDefaults.
+func NewDeleteTransactionParams() *DeleteTransactionParams {
+	return &DeleteTransactionParams{
+		timeout: cr.DefaultTimeout,
+	}
+}
+
+// NewDeleteTransactionParamsWithTimeout creates a new DeleteTransactionParams object
+// with the ability to set a timeout on a request.
+func NewDeleteTransactionParamsWithTimeout(timeout time.Duration) *DeleteTransactionParams {
+	return &DeleteTransactionParams{
+		timeout: timeout,
+	}
+}
+
+// NewDeleteTransactionParamsWithContext creates a new DeleteTransactionParams object
+// with the ability to set a context for a request.
+func NewDeleteTransactionParamsWithContext(ctx context.Context) *DeleteTransactionParams {
+	return &DeleteTransactionParams{
+		Context: ctx,
+	}
+}
+
+// NewDeleteTransactionParamsWithHTTPClient creates a new DeleteTransactionParams object
+// with the ability to set a custom HTTPClient for a request.
+func NewDeleteTransactionParamsWithHTTPClient(client *http.Client) *DeleteTransactionParams {
+	return &DeleteTransactionParams{
+		HTTPClient: client,
+	}
+}
+
+/*
+DeleteTransactionParams contains all the parameters to send to the API endpoint
+
+	for the delete transaction operation.
+
+	Typically these are written to a http.Request.
+*/
+type DeleteTransactionParams struct {
+
+	/* BudgetID.
+
+	   The id of the budget. "last-used" can be used to specify the last used budget and "default" can be used if default budget selection is enabled (see: https://api.youneedabudget.com/#oauth-default-budget).
+	*/
+	BudgetID string
+
+	/* TransactionID.
+
+	   The id of the transaction
+	*/
+	TransactionID string
+
+	timeout    time.Duration
+	Context    context.Context
+	HTTPClient *http.Client
+}
+
+// WithDefaults hydrates default values in the delete transaction params (not the query body).
+//
+// All values with no default are reset to their zero value.
+func (o *DeleteTransactionParams) WithDefaults() *DeleteTransactionParams {
+	o.SetDefaults()
+	return o
+}
+
+// SetDefaults hydrates default values in the delete transaction params (not the query body).
+//
+// All values with no default are reset to their zero value.
+func (o *DeleteTransactionParams) SetDefaults() {
+	// no default values defined for this parameter
+}
+
+// WithTimeout adds the timeout to the delete transaction params
+func (o *DeleteTransactionParams) WithTimeout(timeout time.Duration) *DeleteTransactionParams {
+	o.SetTimeout(timeout)
+	return o
+}
+
+// SetTimeout adds the timeout to the delete transaction params
+func (o *DeleteTransactionParams) SetTimeout(timeout time.Duration) {
+	o.timeout = timeout
+}
+
+// WithContext adds the context to the delete transaction params
+func (o *DeleteTransactionParams) WithContext(ctx context.Context) *DeleteTransactionParams {
+	o.SetContext(ctx)
+	return o
+}
+
+// SetContext adds the context to the delete transaction params
+func (o *DeleteTransactionParams) SetContext(ctx context.Context) {
+	o.Context = ctx
+}
+
+// WithHTTPClient adds the HTTPClient to the delete transaction params
+func (o *DeleteTransactionParams) WithHTTPClient(client *http.Client) *DeleteTransactionParams {
+	o.SetHTTPClient(client)
+	return o
+}
+
+// SetHTTPClient adds the HTTPClient to the delete transaction params
+func (o *DeleteTransactionParams) SetHTTPClient(client *http.Client) {
+	o.HTTPClient = client
+}
+
+// WithBudgetID adds the budgetID to the delete transaction params
+func (o *DeleteTransactionParams) WithBudgetID(budgetID string) *DeleteTransactionParams {
+	o.SetBudgetID(budgetID)
+	return o
+}
+
+// SetBudgetID adds the budgetId to the delete transaction params
+func (o *DeleteTransactionParams) SetBudgetID(budgetID string) {
+	o.BudgetID = budgetID
+}
+
+// WithTransactionID adds the transactionID to the delete transaction params
+func (o *DeleteTransactionParams) WithTransactionID(transactionID string) *DeleteTransactionParams {
+	o.SetTransactionID(transactionID)
+	return o
+}
+
+// SetTransactionID adds the transactionId to the delete transaction params
+func (o *DeleteTransactionParams) SetTransactionID(transactionID string) {
+	o.TransactionID = transactionID
+}
+
+// WriteToRequest writes these params to a swagger request
+func (o *DeleteTransactionParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
+
+	if err := r.SetTimeout(o.timeout); err != nil {
+		return err
+	}
+	var res []error
+
+	// path param budget_id
+	if err := r.SetPathParam("budget_id", o.BudgetID); err != nil {
+		return err
+	}
+
+	// path param transaction_id
+	if err := r.SetPathParam("transaction_id", o.TransactionID); err != nil {
+		return err
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
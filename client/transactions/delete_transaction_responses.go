@@ -0,0 +1,177 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package transactions
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/dbinit/ynab-amazon-import/models"
+)
+
+// DeleteTransactionReader is a Reader for the DeleteTransaction structure.
+type DeleteTransactionReader struct {
+	formats strfmt.Registry
+}
+
+// ReadResponse reads a server response into the received o.
+func (o *DeleteTransactionReader) ReadResponse(response runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	switch response.Code() {
+	case 200:
+		result := NewDeleteTransactionOK()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return result, nil
+	case 404:
+		result := NewDeleteTransactionNotFound()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+	default:
+		return nil, runtime.NewAPIError("response status code does not match any response statuses defined for this endpoint in the swagger spec", response, response.Code())
+	}
+}
+
+// NewDeleteTransactionOK creates a DeleteTransactionOK with default headers values
+func NewDeleteTransactionOK() *DeleteTransactionOK {
+	return &DeleteTransactionOK{}
+}
+
+/*
+DeleteTransactionOK describes a response with status code 200, with default header values.
+
+The transaction was successfully deleted
+*/
+type DeleteTransactionOK struct {
+	Payload *models.TransactionResponse
+}
+
+// IsSuccess returns true when this delete transaction Ok response has a 2xx status code
+func (o *DeleteTransactionOK) IsSuccess() bool {
+	return true
+}
+
+// IsRedirect returns true when this delete transaction Ok response has a 3xx status code
+func (o *DeleteTransactionOK) IsRedirect() bool {
+	return false
+}
+
+// IsClientError returns true when this delete transaction Ok response has a 4xx status code
+func (o *DeleteTransactionOK) IsClientError() bool {
+	return false
+}
+
+// IsServerError returns true when this delete transaction Ok response has a 5xx status code
+func (o *DeleteTransactionOK) IsServerError() bool {
+	return false
+}
+
+// IsCode returns true when this delete transaction Ok response a status code equal to that given
+func (o *DeleteTransactionOK) IsCode(code int) bool {
+	return code == 200
+}
+
+// Code gets the status code for the delete transaction Ok response
+func (o *DeleteTransactionOK) Code() int {
+	return 200
+}
+
+func (o *DeleteTransactionOK) Error() string {
+	return fmt.Sprintf("[DELETE /budgets/{budget_id}/transactions/{transaction_id}][%d] deleteTransactionOk  %+v", 200, o.Payload)
+}
+
+func (o *DeleteTransactionOK) String() string {
+	return fmt.Sprintf("[DELETE /budgets/{budget_id}/transactions/{transaction_id}][%d] deleteTransactionOk  %+v", 200, o.Payload)
+}
+
+func (o *DeleteTransactionOK) GetPayload() *models.TransactionResponse {
+	return o.Payload
+}
+
+func (o *DeleteTransactionOK) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	o.Payload = new(models.TransactionResponse)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewDeleteTransactionNotFound creates a DeleteTransactionNotFound with default headers values
+func NewDeleteTransactionNotFound() *DeleteTransactionNotFound {
+	return &DeleteTransactionNotFound{}
+}
+
+/*
+DeleteTransactionNotFound describes a response with status code 404, with default header values.
+
+The transaction was not found
+*/
+type DeleteTransactionNotFound struct {
+	Payload *models.ErrorResponse
+}
+
+// IsSuccess returns true when this delete transaction not found response has a 2xx status code
+func (o *DeleteTransactionNotFound) IsSuccess() bool {
+	return false
+}
+
+// IsRedirect returns true when this delete transaction not found response has a 3xx status code
+func (o *DeleteTransactionNotFound) IsRedirect() bool {
+	return false
+}
+
+// IsClientError returns true when this delete transaction not found response has a 4xx status code
+func (o *DeleteTransactionNotFound) IsClientError() bool {
+	return true
+}
+
+// IsServerError returns true when this delete transaction not found response has a 5xx status code
+func (o *DeleteTransactionNotFound) IsServerError() bool {
+	return false
+}
+
+// IsCode returns true when this delete transaction not found response a status code equal to that given
+func (o *DeleteTransactionNotFound) IsCode(code int) bool {
+	return code == 404
+}
+
+// Code gets the status code for the delete transaction not found response
+func (o *DeleteTransactionNotFound) Code() int {
+	return 404
+}
+
+func (o *DeleteTransactionNotFound) Error() string {
+	return fmt.Sprintf("[DELETE /budgets/{budget_id}/transactions/{transaction_id}][%d] deleteTransactionNotFound  %+v", 404, o.Payload)
+}
+
+func (o *DeleteTransactionNotFound) String() string {
+	return fmt.Sprintf("[DELETE /budgets/{budget_id}/transactions/{transaction_id}][%d] deleteTransactionNotFound  %+v", 404, o.Payload)
+}
+
+func (o *DeleteTransactionNotFound) GetPayload() *models.ErrorResponse {
+	return o.Payload
+}
+
+func (o *DeleteTransactionNotFound) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	o.Payload = new(models.ErrorResponse)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package transactions
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	cr "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+)
+
+// NewGetTransactionByIDParams creates a new GetTransactionByIDParams object,
+// with the default timeout for this client.
+//
+// Default values are not hydrated, since defaults are normally applied by the API server side.
+//
+// To enforce default values in parameter, use SetDefaults or WithDefaults.
+func NewGetTransactionByIDParams() *GetTransactionByIDParams {
+	return &GetTransactionByIDParams{
+		timeout: cr.DefaultTimeout,
+	}
+}
+
+// NewGetTransactionByIDParamsWithTimeout creates a new GetTransactionByIDParams object
+// with the ability to set a timeout on a request.
+func NewGetTransactionByIDParamsWithTimeout(timeout time.Duration) *GetTransactionByIDParams {
+	return &GetTransactionByIDParams{
+		timeout: timeout,
+	}
+}
+
+// NewGetTransactionByIDParamsWithContext creates a new GetTransactionByIDParams object
+// with the ability to set a context for a request.
+func NewGetTransactionByIDParamsWithContext(ctx context.Context) *GetTransactionByIDParams {
+	return &GetTransactionByIDParams{
+		Context: ctx,
+	}
+}
+
+// NewGetTransactionByIDParamsWithHTTPClient creates a new GetTransactionByIDParams object
+// with the ability to set a custom HTTPClient for a request.
+func NewGetTransactionByIDParamsWithHTTPClient(client *http.Client) *GetTransactionByIDParams {
+	return &GetTransactionByIDParams{
+		HTTPClient: client,
+	}
+}
+
+/*
+GetTransactionByIDParams contains all the parameters to send to the API endpoint
+
+	for the get transaction by Id operation.
+
+	Typically these are written to a http.Request.
+*/
+type GetTransactionByIDParams struct {
+
+	/* BudgetID.
+
+	   The id of the budget. "last-used" can be used to specify the last used budget and "default" can be used if default budget selection is enabled (see: https://api.youneedabudget.com/#oauth-default-budget).
+	*/
+	BudgetID string
+
+	/* TransactionID.
+
+	   The id of the transaction
+	*/
+	TransactionID string
+
+	timeout    time.Duration
+	Context    context.Context
+	HTTPClient *http.Client
+}
+
+// WithDefaults hydrates default values in the get transaction by Id params (not the query body).
+//
+// All values with no default are reset to their zero value.
+func (o *GetTransactionByIDParams) WithDefaults() *GetTransactionByIDParams {
+	o.SetDefaults()
+	return o
+}
+
+// SetDefaults hydrates default values in the get transaction by Id params (not the query body).
+//
+// All values with no default are reset to their zero value.
+func (o *GetTransactionByIDParams) SetDefaults() {
+	// no default values defined for this parameter
+}
+
+// WithTimeout adds the timeout to the get transaction by Id params
+func (o *GetTransactionByIDParams) WithTimeout(timeout time.Duration) *GetTransactionByIDParams {
+	o.SetTimeout(timeout)
+	return o
+}
+
+// SetTimeout adds the timeout to the get transaction by Id params
+func (o *GetTransactionByIDParams) SetTimeout(timeout time.Duration) {
+	o.timeout = timeout
+}
+
+// WithContext adds the context to the get transaction by Id params
+func (o *GetTransactionByIDParams) WithContext(ctx context.Context) *GetTransactionByIDParams {
+	o.SetContext(ctx)
+	return o
+}
+
+// SetContext adds the context to the get transaction by Id params
+func (o *GetTransactionByIDParams) SetContext(ctx context.Context) {
+	o.Context = ctx
+}
+
+// WithHTTPClient adds the HTTPClient to the get transaction by Id params
+func (o *GetTransactionByIDParams) WithHTTPClient(client *http.Client) *GetTransactionByIDParams {
+	o.SetHTTPClient(client)
+	return o
+}
+
+// SetHTTPClient adds the HTTPClient to the get transaction by Id params
+func (o *GetTransactionByIDParams) SetHTTPClient(client *http.Client) {
+	o.HTTPClient = client
+}
+
+// WithBudgetID adds the budgetID to the get transaction by Id params
+func (o *GetTransactionByIDParams) WithBudgetID(budgetID string) *GetTransactionByIDParams {
+	o.SetBudgetID(budgetID)
+	return o
+}
+
+// SetBudgetID adds the budgetId to the get transaction by Id params
+func (o *GetTransactionByIDParams) SetBudgetID(budgetID string) {
+	o.BudgetID = budgetID
+}
+
+// WithTransactionID adds the transactionID to the get transaction by Id params
+func (o *GetTransactionByIDParams) WithTransactionID(transactionID string) *GetTransactionByIDParams {
+	o.SetTransactionID(transactionID)
+	return o
+}
+
+// SetTransactionID adds the transactionId to the get transaction by Id params
+func (o *GetTransactionByIDParams) SetTransactionID(transactionID string) {
+	o.TransactionID = transactionID
+}
+
+// WriteToRequest writes these params to a swagger request
+func (o *GetTransactionByIDParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
+
+	if err := r.SetTimeout(o.timeout); err != nil {
+		return err
+	}
+	var res []error
+
+	// path param budget_id
+	if err := r.SetPathParam("budget_id", o.BudgetID); err != nil {
+		return err
+	}
+
+	// path param transaction_id
+	if err := r.SetPathParam("transaction_id", o.TransactionID); err != nil {
+		return err
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package transactions
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/dbinit/ynab-amazon-import/models"
+)
+
+// GetTransactionByIDReader is a Reader for the GetTransactionByID structure.
+type GetTransactionByIDReader struct {
+	formats strfmt.Registry
+}
+
+// ReadResponse reads a server response into the received o.
+func (o *GetTransactionByIDReader) ReadResponse(response runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	switch response.Code() {
+	case 200:
+		result := NewGetTransactionByIDOK()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return result, nil
+	case 404:
+		result := NewGetTransactionByIDNotFound()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+	default:
+		result := NewGetTransactionByIDDefault(response.Code())
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		if response.Code()/100 == 2 {
+			return result, nil
+		}
+		return nil, result
+	}
+}
+
+// NewGetTransactionByIDOK creates a GetTransactionByIDOK with default headers values
+func NewGetTransactionByIDOK() *GetTransactionByIDOK {
+	return &GetTransactionByIDOK{}
+}
+
+/*
+GetTransactionByIDOK describes a response with status code 200, with default header values.
+
+The requested transaction
+*/
+type GetTransactionByIDOK struct {
+	Payload *models.TransactionResponse
+}
+
+// IsSuccess returns true when this get transaction by Id Ok response has a 2xx status code
+func (o *GetTransactionByIDOK) IsSuccess() bool {
+	return true
+}
+
+// IsRedirect returns true when this get transaction by Id Ok response has a 3xx status code
+func (o *GetTransactionByIDOK) IsRedirect() bool {
+	return false
+}
+
+// IsClientError returns true when this get transaction by Id Ok response has a 4xx status code
+func (o *GetTransactionByIDOK) IsClientError() bool {
+	return false
+}
+
+// IsServerError returns true when this get transaction by Id Ok response has a 5xx status code
+func (o *GetTransactionByIDOK) IsServerError() bool {
+	return false
+}
+
+// IsCode returns true when this get transaction by Id Ok response a status code equal to that given
+func (o *GetTransactionByIDOK) IsCode(code int) bool {
+	return code == 200
+}
+
+// Code gets the status code for the get transaction by Id Ok response
+func (o *GetTransactionByIDOK) Code() int {
+	return 200
+}
+
+func (o *GetTransactionByIDOK) Error() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/transactions/{transaction_id}][%d] getTransactionByIdOk  %+v", 200, o.Payload)
+}
+
+func (o *GetTransactionByIDOK) String() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/transactions/{transaction_id}][%d] getTransactionByIdOk  %+v", 200, o.Payload)
+}
+
+func (o *GetTransactionByIDOK) GetPayload() *models.TransactionResponse {
+	return o.Payload
+}
+
+func (o *GetTransactionByIDOK) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	o.Payload = new(models.TransactionResponse)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewGetTransactionByIDNotFound creates a GetTransactionByIDNotFound with default headers values
+func NewGetTransactionByIDNotFound() *GetTransactionByIDNotFound {
+	return &GetTransactionByIDNotFound{}
+}
+
+/*
+GetTransactionByIDNotFound describes a response with status code 404, with default header values.
+
+The transaction was not found
+*/
+type GetTransactionByIDNotFound struct {
+	Payload *models.ErrorResponse
+}
+
+// IsSuccess returns true when this get transaction by Id not found response has a 2xx status code
+func (o *GetTransactionByIDNotFound) IsSuccess() bool {
+	return false
+}
+
+// IsRedirect returns true when this get transaction by Id not found response has a 3xx status code
+func (o *GetTransactionByIDNotFound) IsRedirect() bool {
+	return false
+}
+
+// IsClientError returns true when this get transaction by Id not found response has a 4xx status code
+func (o *GetTransactionByIDNotFound) IsClientError() bool {
+	return true
+}
+
+// IsServerError returns true when this get transaction by Id not found response has a 5xx status code
+func (o *GetTransactionByIDNotFound) IsServerError() bool {
+	return false
+}
+
+// IsCode returns true when this get transaction by Id not found response a status code equal to that given
+func (o *GetTransactionByIDNotFound) IsCode(code int) bool {
+	return code == 404
+}
+
+// Code gets the status code for the get transaction by Id not found response
+func (o *GetTransactionByIDNotFound) Code() int {
+	return 404
+}
+
+func (o *GetTransactionByIDNotFound) Error() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/transactions/{transaction_id}][%d] getTransactionByIdNotFound  %+v", 404, o.Payload)
+}
+
+func (o *GetTransactionByIDNotFound) String() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/transactions/{transaction_id}][%d] getTransactionByIdNotFound  %+v", 404, o.Payload)
+}
+
+func (o *GetTransactionByIDNotFound) GetPayload() *models.ErrorResponse {
+	return o.Payload
+}
+
+func (o *GetTransactionByIDNotFound) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	o.Payload = new(models.ErrorResponse)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewGetTransactionByIDDefault creates a GetTransactionByIDDefault with default headers values
+func NewGetTransactionByIDDefault(code int) *GetTransactionByIDDefault {
+	return &GetTransactionByIDDefault{
+		_statusCode: code,
+	}
+}
+
+/*
+GetTransactionByIDDefault describes a response with status code -1, with default header values.
+
+An error occurred
+*/
+type GetTransactionByIDDefault struct {
+	_statusCode int
+
+	Payload *models.ErrorResponse
+}
+
+// IsSuccess returns true when this get transaction by Id default response has a 2xx status code
+func (o *GetTransactionByIDDefault) IsSuccess() bool {
+	return o._statusCode/100 == 2
+}
+
+// IsRedirect returns true when this get transaction by Id default response has a 3xx status code
+func (o *GetTransactionByIDDefault) IsRedirect() bool {
+	return o._statusCode/100 == 3
+}
+
+// IsClientError returns true when this get transaction by Id default response has a 4xx status code
+func (o *GetTransactionByIDDefault) IsClientError() bool {
+	return o._statusCode/100 == 4
+}
+
+// IsServerError returns true when this get transaction by Id default response has a 5xx status code
+func (o *GetTransactionByIDDefault) IsServerError() bool {
+	return o._statusCode/100 == 5
+}
+
+// IsCode returns true when this get transaction by Id default response a status code equal to that given
+func (o *GetTransactionByIDDefault) IsCode(code int) bool {
+	return o._statusCode == code
+}
+
+// Code gets the status code for the get transaction by Id default response
+func (o *GetTransactionByIDDefault) Code() int {
+	return o._statusCode
+}
+
+func (o *GetTransactionByIDDefault) Error() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/transactions/{transaction_id}][%d] getTransactionById default  %+v", o._statusCode, o.Payload)
+}
+
+func (o *GetTransactionByIDDefault) String() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/transactions/{transaction_id}][%d] getTransactionById default  %+v", o._statusCode, o.Payload)
+}
+
+func (o *GetTransactionByIDDefault) GetPayload() *models.ErrorResponse {
+	return o.Payload
+}
+
+func (o *GetTransactionByIDDefault) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	o.Payload = new(models.ErrorResponse)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,280 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package transactions
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	cr "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+)
+
+// NewGetTransactionsByAccountParams creates a new GetTransactionsByAccountParams object,
+// with the default timeout for this client.
+//
+// Default values are not hydrated, since defaults are normally applied by the API server side.
+//
+// To enforce default values in parameter, use SetDefaults or WithDefaults.
+func NewGetTransactionsByAccountParams() *GetTransactionsByAccountParams {
+	return &GetTransactionsByAccountParams{
+		timeout: cr.DefaultTimeout,
+	}
+}
+
+// NewGetTransactionsByAccountParamsWithTimeout creates a new GetTransactionsByAccountParams object
+// with the ability to set a timeout on a request.
+func NewGetTransactionsByAccountParamsWithTimeout(timeout time.Duration) *GetTransactionsByAccountParams {
+	return &GetTransactionsByAccountParams{
+		timeout: timeout,
+	}
+}
+
+// NewGetTransactionsByAccountParamsWithContext creates a new GetTransactionsByAccountParams object
+// with the ability to set a context for a request.
+func NewGetTransactionsByAccountParamsWithContext(ctx context.Context) *GetTransactionsByAccountParams {
+	return &GetTransactionsByAccountParams{
+		Context: ctx,
+	}
+}
+
+// NewGetTransactionsByAccountParamsWithHTTPClient creates a new GetTransactionsByAccountParams object
+// with the ability to set a custom HTTPClient for a request.
+func NewGetTransactionsByAccountParamsWithHTTPClient(client *http.Client) *GetTransactionsByAccountParams {
+	return &GetTransactionsByAccountParams{
+		HTTPClient: client,
+	}
+}
+
+/*
+GetTransactionsByAccountParams contains all the parameters to send to the API endpoint
+
+	for the get transactions by account operation.
+
+	Typically these are written to a http.Request.
+*/
+type GetTransactionsByAccountParams struct {
+
+	/* AccountID.
+
+	   The id of the account
+	*/
+	AccountID string
+
+	/* BudgetID.
+
+	   The id of the budget. "last-used" can be used to specify the last used budget and "default" can be used if default budget selection is enabled (see: https://api.youneedabudget.com/#oauth-default-budget).
+	*/
+	BudgetID string
+
+	/* LastKnowledgeOfServer.
+
+	   The starting server knowledge.  If provided, only entities that have changed since `last_knowledge_of_server` will be included.
+
+	   Format: int64
+	*/
+	LastKnowledgeOfServer *int64
+
+	/* SinceDate.
+
+	   If specified, only transactions on or after this date will be included.  The date should be ISO formatted (e.g. 2016-12-30).
+
+	   Format: date
+	*/
+	SinceDate *strfmt.Date
+
+	/* Type.
+
+	   If specified, only transactions of the specified type will be included. "uncategorized" and "unapproved" are currently supported.
+	*/
+	Type *string
+
+	timeout    time.Duration
+	Context    context.Context
+	HTTPClient *http.Client
+}
+
+// WithDefaults hydrates default values in the get transactions by account params (not the query body).
+//
+// All values with no default are reset to their zero value.
+func (o *GetTransactionsByAccountParams) WithDefaults() *GetTransactionsByAccountParams {
+	o.SetDefaults()
+	return o
+}
+
+// SetDefaults hydrates default values in the get transactions by account params (not the query body).
+//
+// All values with no default are reset to their zero value.
+func (o *GetTransactionsByAccountParams) SetDefaults() {
+	// no default values defined for this parameter
+}
+
+// WithTimeout adds the timeout to the get transactions by account params
+func (o *GetTransactionsByAccountParams) WithTimeout(timeout time.Duration) *GetTransactionsByAccountParams {
+	o.SetTimeout(timeout)
+	return o
+}
+
+// SetTimeout adds the timeout to the get transactions by account params
+func (o *GetTransactionsByAccountParams) SetTimeout(timeout time.Duration) {
+	o.timeout = timeout
+}
+
+// WithContext adds the context to the get transactions by account params
+func (o *GetTransactionsByAccountParams) WithContext(ctx context.Context) *GetTransactionsByAccountParams {
+	o.SetContext(ctx)
+	return o
+}
+
+// SetContext adds the context to the get transactions by account params
+func (o *GetTransactionsByAccountParams) SetContext(ctx context.Context) {
+	o.Context = ctx
+}
+
+// WithHTTPClient adds the HTTPClient to the get transactions by account params
+func (o *GetTransactionsByAccountParams) WithHTTPClient(client *http.Client) *GetTransactionsByAccountParams {
+	o.SetHTTPClient(client)
+	return o
+}
+
+// SetHTTPClient adds the HTTPClient to the get transactions by account params
+func (o *GetTransactionsByAccountParams) SetHTTPClient(client *http.Client) {
+	o.HTTPClient = client
+}
+
+// WithAccountID adds the accountID to the get transactions by account params
+func (o *GetTransactionsByAccountParams) WithAccountID(accountID string) *GetTransactionsByAccountParams {
+	o.SetAccountID(accountID)
+	return o
+}
+
+// SetAccountID adds the accountId to the get transactions by account params
+func (o *GetTransactionsByAccountParams) SetAccountID(accountID string) {
+	o.AccountID = accountID
+}
+
+// WithBudgetID adds the budgetID to the get transactions by account params
+func (o *GetTransactionsByAccountParams) WithBudgetID(budgetID string) *GetTransactionsByAccountParams {
+	o.SetBudgetID(budgetID)
+	return o
+}
+
+// SetBudgetID adds the budgetId to the get transactions by account params
+func (o *GetTransactionsByAccountParams) SetBudgetID(budgetID string) {
+	o.BudgetID = budgetID
+}
+
+// WithLastKnowledgeOfServer adds the lastKnowledgeOfServer to the get transactions by account params
+func (o *GetTransactionsByAccountParams) WithLastKnowledgeOfServer(lastKnowledgeOfServer *int64) *GetTransactionsByAccountParams {
+	o.SetLastKnowledgeOfServer(lastKnowledgeOfServer)
+	return o
+}
+
+// SetLastKnowledgeOfServer adds the lastKnowledgeOfServer to the get transactions by account params
+func (o *GetTransactionsByAccountParams) SetLastKnowledgeOfServer(lastKnowledgeOfServer *int64) {
+	o.LastKnowledgeOfServer = lastKnowledgeOfServer
+}
+
+// WithSinceDate adds the sinceDate to the get transactions by account params
+func (o *GetTransactionsByAccountParams) WithSinceDate(sinceDate *strfmt.Date) *GetTransactionsByAccountParams {
+	o.SetSinceDate(sinceDate)
+	return o
+}
+
+// SetSinceDate adds the sinceDate to the get transactions by account params
+func (o *GetTransactionsByAccountParams) SetSinceDate(sinceDate *strfmt.Date) {
+	o.SinceDate = sinceDate
+}
+
+// WithType adds the typeVar to the get transactions by account params
+func (o *GetTransactionsByAccountParams) WithType(typeVar *string) *GetTransactionsByAccountParams {
+	o.SetType(typeVar)
+	return o
+}
+
+// SetType adds the type to the get transactions by account params
+func (o *GetTransactionsByAccountParams) SetType(typeVar *string) {
+	o.Type = typeVar
+}
+
+// WriteToRequest writes these params to a swagger request
+func (o *GetTransactionsByAccountParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
+
+	if err := r.SetTimeout(o.timeout); err != nil {
+		return err
+	}
+	var res []error
+
+	// path param account_id
+	if err := r.SetPathParam("account_id", o.AccountID); err != nil {
+		return err
+	}
+
+	// path param budget_id
+	if err := r.SetPathParam("budget_id", o.BudgetID); err != nil {
+		return err
+	}
+
+	if o.LastKnowledgeOfServer != nil {
+
+		// query param last_knowledge_of_server
+		var qrLastKnowledgeOfServer int64
+
+		if o.LastKnowledgeOfServer != nil {
+			qrLastKnowledgeOfServer = *o.LastKnowledgeOfServer
+		}
+		qLastKnowledgeOfServer := swag.FormatInt64(qrLastKnowledgeOfServer)
+		if qLastKnowledgeOfServer != "" {
+
+			if err := r.SetQueryParam("last_knowledge_of_server", qLastKnowledgeOfServer); err != nil {
+				return err
+			}
+		}
+	}
+
+	if o.SinceDate != nil {
+
+		// query param since_date
+		var qrSinceDate strfmt.Date
+
+		if o.SinceDate != nil {
+			qrSinceDate = *o.SinceDate
+		}
+		qSinceDate := qrSinceDate.String()
+		if qSinceDate != "" {
+
+			if err := r.SetQueryParam("since_date", qSinceDate); err != nil {
+				return err
+			}
+		}
+	}
+
+	if o.Type != nil {
+
+		// query param type
+		var qrType string
+
+		if o.Type != nil {
+			qrType = *o.Type
+		}
+		qType := qrType
+		if qType != "" {
+
+			if err := r.SetQueryParam("type", qType); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package transactions
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/dbinit/ynab-amazon-import/models"
+)
+
+// GetTransactionsByAccountReader is a Reader for the GetTransactionsByAccount structure.
+type GetTransactionsByAccountReader struct {
+	formats strfmt.Registry
+}
+
+// ReadResponse reads a server response into the received o.
+func (o *GetTransactionsByAccountReader) ReadResponse(response runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	switch response.Code() {
+	case 200:
+		result := NewGetTransactionsByAccountOK()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return result, nil
+	case 404:
+		result := NewGetTransactionsByAccountNotFound()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+	default:
+		result := NewGetTransactionsByAccountDefault(response.Code())
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		if response.Code()/100 == 2 {
+			return result, nil
+		}
+		return nil, result
+	}
+}
+
+// NewGetTransactionsByAccountOK creates a GetTransactionsByAccountOK with default headers values
+func NewGetTransactionsByAccountOK() *GetTransactionsByAccountOK {
+	return &GetTransactionsByAccountOK{}
+}
+
+/*
+GetTransactionsByAccountOK describes a response with status code 200, with default header values.
+
+The list of requested transactions
+*/
+type GetTransactionsByAccountOK struct {
+	Payload *models.TransactionsResponse
+}
+
+// IsSuccess returns true when this get transactions by account Ok response has a 2xx status code
+func (o *GetTransactionsByAccountOK) IsSuccess() bool {
+	return true
+}
+
+// IsRedirect returns true when this get transactions by account Ok response has a 3xx status code
+func (o *GetTransactionsByAccountOK) IsRedirect() bool {
+	return false
+}
+
+// IsClientError returns true when this get transactions by account Ok response has a 4xx status code
+func (o *GetTransactionsByAccountOK) IsClientError() bool {
+	return false
+}
+
+// IsServerError returns true when this get transactions by account Ok response has a 5xx status code
+func (o *GetTransactionsByAccountOK) IsServerError() bool {
+	return false
+}
+
+// IsCode returns true when this get transactions by account Ok response a status code equal to that given
+func (o *GetTransactionsByAccountOK) IsCode(code int) bool {
+	return code == 200
+}
+
+// Code gets the status code for the get transactions by account Ok response
+func (o *GetTransactionsByAccountOK) Code() int {
+	return 200
+}
+
+func (o *GetTransactionsByAccountOK) Error() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/accounts/{account_id}/transactions][%d] getTransactionsByAccountOk  %+v", 200, o.Payload)
+}
+
+func (o *GetTransactionsByAccountOK) String() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/accounts/{account_id}/transactions][%d] getTransactionsByAccountOk  %+v", 200, o.Payload)
+}
+
+func (o *GetTransactionsByAccountOK) GetPayload() *models.TransactionsResponse {
+	return o.Payload
+}
+
+func (o *GetTransactionsByAccountOK) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	o.Payload = new(models.TransactionsResponse)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewGetTransactionsByAccountNotFound creates a GetTransactionsByAccountNotFound with default headers values
+func NewGetTransactionsByAccountNotFound() *GetTransactionsByAccountNotFound {
+	return &GetTransactionsByAccountNotFound{}
+}
+
+/*
+GetTransactionsByAccountNotFound describes a response with status code 404, with default header values.
+
+No transactions were found
+*/
+type GetTransactionsByAccountNotFound struct {
+	Payload *models.ErrorResponse
+}
+
+// IsSuccess returns true when this get transactions by account not found response has a 2xx status code
+func (o *GetTransactionsByAccountNotFound) IsSuccess() bool {
+	return false
+}
+
+// IsRedirect returns true when this get transactions by account not found response has a 3xx status code
+func (o *GetTransactionsByAccountNotFound) IsRedirect() bool {
+	return false
+}
+
+// IsClientError returns true when this get transactions by account not found response has a 4xx status code
+func (o *GetTransactionsByAccountNotFound) IsClientError() bool {
+	return true
+}
+
+// IsServerError returns true when this get transactions by account not found response has a 5xx status code
+func (o *GetTransactionsByAccountNotFound) IsServerError() bool {
+	return false
+}
+
+// IsCode returns true when this get transactions by account not found response a status code equal to that given
+func (o *GetTransactionsByAccountNotFound) IsCode(code int) bool {
+	return code == 404
+}
+
+// Code gets the status code for the get transactions by account not found response
+func (o *GetTransactionsByAccountNotFound) Code() int {
+	return 404
+}
+
+func (o *GetTransactionsByAccountNotFound) Error() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/accounts/{account_id}/transactions][%d] getTransactionsByAccountNotFound  %+v", 404, o.Payload)
+}
+
+func (o *GetTransactionsByAccountNotFound) String() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/accounts/{account_id}/transactions][%d] getTransactionsByAccountNotFound  %+v", 404, o.Payload)
+}
+
+func (o *GetTransactionsByAccountNotFound) GetPayload() *models.ErrorResponse {
+	return o.Payload
+}
+
+func (o *GetTransactionsByAccountNotFound) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	o.Payload = new(models.ErrorResponse)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewGetTransactionsByAccountDefault creates a GetTransactionsByAccountDefault with default headers values
+func NewGetTransactionsByAccountDefault(code int) *GetTransactionsByAccountDefault {
+	return &GetTransactionsByAccountDefault{
+		_statusCode: code,
+	}
+}
+
+/*
+GetTransactionsByAccountDefault describes a response with status code -1, with default header values.
+
+An error occurred
+*/
+type GetTransactionsByAccountDefault struct {
+	_statusCode int
+
+	Payload *models.ErrorResponse
+}
+
+// IsSuccess returns true when this get transactions by account default response has a 2xx status code
+func (o *GetTransactionsByAccountDefault) IsSuccess() bool {
+	return o._statusCode/100 == 2
+}
+
+// IsRedirect returns true when this get transactions by account default response has a 3xx status code
+func (o *GetTransactionsByAccountDefault) IsRedirect() bool {
+	return o._statusCode/100 == 3
+}
+
+// IsClientError returns true when this get transactions by account default response has a 4xx status code
+func (o *GetTransactionsByAccountDefault) IsClientError() bool {
+	return o._statusCode/100 == 4
+}
+
+// IsServerError returns true when this get transactions by account default response has a 5xx status code
+func (o *GetTransactionsByAccountDefault) IsServerError() bool {
+	return o._statusCode/100 == 5
+}
+
+// IsCode returns true when this get transactions by account default response a status code equal to that given
+func (o *GetTransactionsByAccountDefault) IsCode(code int) bool {
+	return o._statusCode == code
+}
+
+// Code gets the status code for the get transactions by account default response
+func (o *GetTransactionsByAccountDefault) Code() int {
+	return o._statusCode
+}
+
+func (o *GetTransactionsByAccountDefault) Error() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/accounts/{account_id}/transactions][%d] getTransactionsByAccount default  %+v", o._statusCode, o.Payload)
+}
+
+func (o *GetTransactionsByAccountDefault) String() string {
+	return fmt.Sprintf("[GET /budgets/{budget_id}/accounts/{account_id}/transactions][%d] getTransactionsByAccount default  %+v", o._statusCode, o.Payload)
+}
+
+func (o *GetTransactionsByAccountDefault) GetPayload() *models.ErrorResponse {
+	return o.Payload
+}
+
+func (o *GetTransactionsByAccountDefault) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	o.Payload = new(models.ErrorResponse)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
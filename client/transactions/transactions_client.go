@@ -32,6 +32,12 @@ type ClientOption func(*runtime.ClientOperation)
 type ClientService interface {
 	CreateTransaction(params *CreateTransactionParams, authInfo runtime.ClientAuthInfoWriter, opts ...ClientOption) (*CreateTransactionCreated, error)
 
+	DeleteTransaction(params *DeleteTransactionParams, authInfo runtime.ClientAuthInfoWriter, opts ...ClientOption) (*DeleteTransactionOK, error)
+
+	GetTransactionByID(params *GetTransactionByIDParams, authInfo runtime.ClientAuthInfoWriter, opts ...ClientOption) (*GetTransactionByIDOK, error)
+
+	GetTransactionsByAccount(params *GetTransactionsByAccountParams, authInfo runtime.ClientAuthInfoWriter, opts ...ClientOption) (*GetTransactionsByAccountOK, error)
+
 	SetTransport(transport runtime.ClientTransport)
 }
 
@@ -76,6 +82,127 @@ func (a *Client) CreateTransaction(params *CreateTransactionParams, authInfo run
 	panic(msg)
 }
 
+/*
+DeleteTransaction deletes an existing transaction
+
+Deletes a transaction
+*/
+func (a *Client) DeleteTransaction(params *DeleteTransactionParams, authInfo runtime.ClientAuthInfoWriter, opts ...ClientOption) (*DeleteTransactionOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewDeleteTransactionParams()
+	}
+	op := &runtime.ClientOperation{
+		ID:                 "deleteTransaction",
+		Method:             "DELETE",
+		PathPattern:        "/budgets/{budget_id}/transactions/{transaction_id}",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"https"},
+		Params:             params,
+		Reader:             &DeleteTransactionReader{formats: a.formats},
+		AuthInfo:           authInfo,
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	}
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	result, err := a.transport.Submit(op)
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*DeleteTransactionOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for deleteTransaction: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
+/*
+GetTransactionByID singles transaction
+
+Returns a single transaction
+*/
+func (a *Client) GetTransactionByID(params *GetTransactionByIDParams, authInfo runtime.ClientAuthInfoWriter, opts ...ClientOption) (*GetTransactionByIDOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetTransactionByIDParams()
+	}
+	op := &runtime.ClientOperation{
+		ID:                 "getTransactionById",
+		Method:             "GET",
+		PathPattern:        "/budgets/{budget_id}/transactions/{transaction_id}",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"https"},
+		Params:             params,
+		Reader:             &GetTransactionByIDReader{formats: a.formats},
+		AuthInfo:           authInfo,
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	}
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	result, err := a.transport.Submit(op)
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*GetTransactionByIDOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	unexpectedSuccess := result.(*GetTransactionByIDDefault)
+	return nil, runtime.NewAPIError("unexpected success response: content available as default response in error", unexpectedSuccess, unexpectedSuccess.Code())
+}
+
+/*
+GetTransactionsByAccount lists account transactions
+
+Returns all transactions for a specified account
+*/
+func (a *Client) GetTransactionsByAccount(params *GetTransactionsByAccountParams, authInfo runtime.ClientAuthInfoWriter, opts ...ClientOption) (*GetTransactionsByAccountOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetTransactionsByAccountParams()
+	}
+	op := &runtime.ClientOperation{
+		ID:                 "getTransactionsByAccount",
+		Method:             "GET",
+		PathPattern:        "/budgets/{budget_id}/accounts/{account_id}/transactions",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"https"},
+		Params:             params,
+		Reader:             &GetTransactionsByAccountReader{formats: a.formats},
+		AuthInfo:           authInfo,
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	}
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	result, err := a.transport.Submit(op)
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*GetTransactionsByAccountOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	unexpectedSuccess := result.(*GetTransactionsByAccountDefault)
+	return nil, runtime.NewAPIError("unexpected success response: content available as default response in error", unexpectedSuccess, unexpectedSuccess.Code())
+}
+
 // SetTransport changes the transport on the client
 func (a *Client) SetTransport(transport runtime.ClientTransport) {
 	a.transport = transport
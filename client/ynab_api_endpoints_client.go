@@ -10,7 +10,10 @@ import (
 	httptransport "github.com/go-openapi/runtime/client"
 	"github.com/go-openapi/strfmt"
 
+	"github.com/dbinit/ynab-amazon-import/client/accounts"
 	"github.com/dbinit/ynab-amazon-import/client/budgets"
+	"github.com/dbinit/ynab-amazon-import/client/categories"
+	"github.com/dbinit/ynab-amazon-import/client/payees"
 	"github.com/dbinit/ynab-amazon-import/client/transactions"
 )
 
@@ -56,7 +59,10 @@ func New(transport runtime.ClientTransport, formats strfmt.Registry) *YNABAPIEnd
 
 	cli := new(YNABAPIEndpoints)
 	cli.Transport = transport
+	cli.Accounts = accounts.New(transport, formats)
 	cli.Budgets = budgets.New(transport, formats)
+	cli.Categories = categories.New(transport, formats)
+	cli.Payees = payees.New(transport, formats)
 	cli.Transactions = transactions.New(transport, formats)
 	return cli
 }
@@ -102,8 +108,14 @@ func (cfg *TransportConfig) WithSchemes(schemes []string) *TransportConfig {
 
 // YNABAPIEndpoints is a client for YNAB API endpoints
 type YNABAPIEndpoints struct {
+	Accounts accounts.ClientService
+
 	Budgets budgets.ClientService
 
+	Categories categories.ClientService
+
+	Payees payees.ClientService
+
 	Transactions transactions.ClientService
 
 	Transport runtime.ClientTransport
@@ -112,6 +124,9 @@ type YNABAPIEndpoints struct {
 // SetTransport changes the transport on the client and all its subresources
 func (c *YNABAPIEndpoints) SetTransport(transport runtime.ClientTransport) {
 	c.Transport = transport
+	c.Accounts.SetTransport(transport)
 	c.Budgets.SetTransport(transport)
+	c.Categories.SetTransport(transport)
+	c.Payees.SetTransport(transport)
 	c.Transactions.SetTransport(transport)
 }
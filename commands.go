@@ -0,0 +1,702 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dbinit/ynab-amazon-import/export"
+	"github.com/dbinit/ynab-amazon-import/journal"
+	"github.com/dbinit/ynab-amazon-import/models"
+	"github.com/dbinit/ynab-amazon-import/orders"
+	"github.com/dbinit/ynab-amazon-import/reconcile"
+	"github.com/dbinit/ynab-amazon-import/sink"
+	"github.com/dbinit/ynab-amazon-import/spending"
+	"github.com/dbinit/ynab-amazon-import/txn"
+	"github.com/dbinit/ynab-amazon-import/ynabsync"
+	"github.com/go-openapi/strfmt"
+	"github.com/zalando/go-keyring"
+)
+
+// command is a subcommand of the CLI.
+type command struct {
+	name  string
+	args  string
+	short string
+	setup func(fs *flag.FlagSet, o *options)
+	run   func(ctx context.Context, fs *flag.FlagSet, o *options) error
+}
+
+// commands lists the subcommands in help order.
+var commands = []*command{
+	{
+		name:  "budgets",
+		short: "List the budgets visible to the token",
+		setup: func(fs *flag.FlagSet, o *options) {
+			o.tokenFlags(fs)
+			o.cacheFlags(fs)
+		},
+		run: runBudgets,
+	},
+	{
+		name:  "accounts",
+		short: "List the accounts of a budget",
+		setup: func(fs *flag.FlagSet, o *options) {
+			o.tokenFlags(fs)
+			o.budgetFlags(fs, false)
+			o.cacheFlags(fs)
+		},
+		run: runAccounts,
+	},
+	{
+		name:  "categories",
+		short: "List the categories of a budget",
+		setup: func(fs *flag.FlagSet, o *options) {
+			o.tokenFlags(fs)
+			o.budgetFlags(fs, false)
+			o.cacheFlags(fs)
+		},
+		run: runCategories,
+	},
+	{
+		name:  "import",
+		short: "Import Amazon orders into a YNAB account, or an Actual Budget or Firefly III account",
+		setup: func(fs *flag.FlagSet, o *options) {
+			o.tokenFlags(fs)
+			o.budgetFlags(fs, true)
+			o.cacheFlags(fs)
+			o.inputFlags(fs, true)
+			o.buildFlags(fs)
+			o.postFlags(fs)
+			o.journalFlags(fs)
+			o.sinkFlags(fs)
+			fs.Bool("dry_run", false, "Print the transactions instead of importing them, like preview; only dry runs into YNAB need the budget, account and token")
+			o.dbFlags(fs)
+		},
+		run: runImport,
+	},
+	{
+		name:  "preview",
+		short: "Print the transactions an import would create",
+		setup: func(fs *flag.FlagSet, o *options) {
+			o.tokenFlags(fs)
+			o.budgetFlags(fs, true)
+			o.cacheFlags(fs)
+			o.inputFlags(fs, true)
+			o.buildFlags(fs)
+		},
+		run: runPreview,
+	},
+	{
+		name:  "export",
+		short: "Write the transactions an import would create to a CSV, QIF or OFX file for YNAB's file import, or a plain-text accounting journal, without a token",
+		setup: func(fs *flag.FlagSet, o *options) {
+			o.inputFlags(fs, true)
+			o.buildFlags(fs)
+			fs.String("format", "csv", "Export format: csv, qif, ofx, ledger, hledger or beancount")
+			fs.String("output", "-", "File to write, or - for stdout")
+			fs.String("ledger_account", "Liabilities:Amazon", "Plain-text accounting account that paid for the orders")
+			fs.String("expense_account", "Expenses:Amazon", "Plain-text accounting account for items that no rule assigns an account, and shipping")
+			fs.String("gift_card_account", "Assets:Amazon Gift Card", "Plain-text accounting account that paid for the part of orders paid by gift card, or empty to net it out of the expense account")
+		},
+		run: runExport,
+	},
+	{
+		name:  "match",
+		short: "Match Amazon orders to existing transactions in a YNAB account",
+		setup: func(fs *flag.FlagSet, o *options) {
+			o.tokenFlags(fs)
+			o.budgetFlags(fs, true)
+			o.cacheFlags(fs)
+			o.inputFlags(fs, true)
+			o.buildFlags(fs)
+			fs.Int("days", 10, "Maximum number of days between matching transactions")
+		},
+		run: runMatch,
+	},
+	{
+		name:  "reconcile",
+		short: "Compare the monthly totals of the Amazon charges assigned to a YNAB account with its Amazon transactions, listing the unmatched ones",
+		setup: func(fs *flag.FlagSet, o *options) {
+			o.tokenFlags(fs)
+			o.budgetFlags(fs, true)
+			o.cacheFlags(fs)
+			o.inputFlags(fs, true)
+			o.buildFlags(fs)
+			fs.Int("days", 10, "Maximum number of days between matching transactions")
+		},
+		run: runReconcile,
+	},
+	{
+		name:  "report merge",
+		short: "Report how Amazon orders and items were merged",
+		setup: func(fs *flag.FlagSet, o *options) {
+			o.inputFlags(fs, false)
+			fs.String("format", "text", "Report format: text or json")
+		},
+		run: runReport,
+	},
+	{
+		name:  "report spending",
+		short: "Report Amazon spending by month, seller, category rule and payment instrument, with shipping, promotion and fee totals and the top items",
+		setup: func(fs *flag.FlagSet, o *options) {
+			o.inputFlags(fs, true)
+			fs.StringVar(&o.rules, "rules", "", "Optional JSON file of categorization rules, whose names categorize the items")
+			fs.String("format", "text", "Report format: text, csv or html")
+			fs.String("output", "-", "File to write, or - for stdout")
+			fs.Int("top", 10, "Number of top items to list, or 0 for all")
+		},
+		run: runSpending,
+	},
+	{
+		name:  "query",
+		args:  "[order ID]",
+		short: "Look up orders in the order database by order ID, item title, ASIN or amount",
+		setup: func(fs *flag.FlagSet, o *options) {
+			o.dbFlags(fs)
+			fs.String("title", "", "Find orders with an item whose title contains this text, ignoring case")
+			fs.String("asin", "", "Find orders with an item with this ASIN/ISBN")
+			fs.String("amount", "", "Find orders charged this amount, or with an item or payment of this amount, e.g. 12.34")
+			fs.String("format", "text", "Output format: text or json")
+		},
+		run: runQuery,
+	},
+	{
+		name:  "undo",
+		args:  "[run]",
+		short: "Delete the transactions created by an import run, or list runs",
+		setup: func(fs *flag.FlagSet, o *options) {
+			o.tokenFlags(fs)
+			o.journalFlags(fs)
+		},
+		run: runUndo,
+	},
+	{
+		name:  "token",
+		args:  "[set|delete]",
+		short: "Show where the token comes from, or set or delete it in the OS secret store",
+		setup: func(fs *flag.FlagSet, o *options) {
+			o.tokenFlags(fs)
+		},
+		run: runToken,
+	},
+	{
+		name:  "config",
+		args:  "[validate]",
+		short: "List the config profiles, or check that their budgets and accounts still exist",
+		setup: func(fs *flag.FlagSet, o *options) {},
+		run:   runConfig,
+	},
+}
+
+// findCommand returns the named command, or nil.
+func findCommand(name string) *command {
+	for _, c := range commands {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// lookupCommand returns the named command and its remaining arguments. Names
+// of commands with subcommands, e.g. "report", take the subcommand from the
+// first argument. It returns nil if there is no such command.
+func lookupCommand(name string, args []string) (*command, []string) {
+	if c := findCommand(name); c != nil {
+		return c, args
+	}
+	if len(args) > 0 {
+		if c := findCommand(name + " " + args[0]); c != nil {
+			return c, args[1:]
+		}
+	}
+	return nil, args
+}
+
+// flagSet returns a flag set for the command with usage that describes it.
+func (c *command) flagSet(o *options) *flag.FlagSet {
+	fs := flag.NewFlagSet(c.name, flag.ExitOnError)
+	o.configFlags(fs)
+	c.setup(fs, o)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: %s %s [flags] %s\n\n%s.\n\nFlags:\n", progName, c.name, c.args, c.short)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// flagInt returns the value of an int flag registered by a command's setup.
+func flagInt(fs *flag.FlagSet, name string) int {
+	return fs.Lookup(name).Value.(flag.Getter).Get().(int)
+}
+
+// flagString returns the value of a string flag registered by a command's
+// setup.
+func flagString(fs *flag.FlagSet, name string) string {
+	return fs.Lookup(name).Value.String()
+}
+
+// runBudgets lists the budgets visible to the token.
+func runBudgets(ctx context.Context, fs *flag.FlagSet, o *options) error {
+	if err := o.resolveToken(); err != nil {
+		return err
+	}
+	l, err := o.metaCache().BudgetList(ctx, true)
+	if err != nil {
+		return err
+	}
+	def := l.Default
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tID\tLAST MODIFIED\tDEFAULT")
+	for _, b := range l.Budgets {
+		if b == nil || b.ID == nil || b.Name == nil {
+			continue
+		}
+		isDefault := def != nil && def.ID != nil && *def.ID == *b.ID
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", *b.Name, b.ID, time.Time(b.LastModifiedOn).Format(time.RFC3339), yesNo(isDefault))
+	}
+	return w.Flush()
+}
+
+// runAccounts lists the accounts of a budget.
+func runAccounts(ctx context.Context, fs *flag.FlagSet, o *options) error {
+	if err := require(fs, "budget"); err != nil {
+		return err
+	}
+	if err := o.resolveToken(); err != nil {
+		return err
+	}
+	b, err := o.metaCache().Budget(ctx, o.budget)
+	if err != nil {
+		return err
+	}
+	as, err := o.metaCache().Accounts(ctx, b.ID)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tID\tTYPE\tON BUDGET\tCLOSED\tBALANCE")
+	for _, a := range as {
+		if a == nil || a.ID == nil || a.Name == nil || (a.Deleted != nil && *a.Deleted) {
+			continue
+		}
+		var typ string
+		if a.Type != nil {
+			typ = string(*a.Type)
+		}
+		var balance int64
+		if a.Balance != nil {
+			balance = *a.Balance
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			*a.Name, a.ID, typ, yesNo(a.OnBudget != nil && *a.OnBudget), yesNo(a.Closed != nil && *a.Closed), orders.FormatMoney(balance))
+	}
+	return w.Flush()
+}
+
+// runCategories lists the categories of a budget.
+func runCategories(ctx context.Context, fs *flag.FlagSet, o *options) error {
+	if err := require(fs, "budget"); err != nil {
+		return err
+	}
+	if err := o.resolveToken(); err != nil {
+		return err
+	}
+	b, err := o.metaCache().Budget(ctx, o.budget)
+	if err != nil {
+		return err
+	}
+	gs, err := o.metaCache().Categories(ctx, b.ID)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "GROUP\tNAME\tID\tHIDDEN")
+	for _, g := range gs {
+		if g == nil || g.Name == nil || (g.Deleted != nil && *g.Deleted) {
+			continue
+		}
+		for _, c := range g.Categories {
+			if c == nil || c.ID == nil || c.Name == nil || (c.Deleted != nil && *c.Deleted) {
+				continue
+			}
+			hidden := (g.Hidden != nil && *g.Hidden) || (c.Hidden != nil && *c.Hidden)
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", *g.Name, *c.Name, c.ID, yesNo(hidden))
+		}
+	}
+	return w.Flush()
+}
+
+// runImport imports Amazon orders into a YNAB account, or the account of
+// another budgeting tool.
+func runImport(ctx context.Context, fs *flag.FlagSet, o *options) error {
+	if flagString(fs, "dry_run") == "true" {
+		// A dry run is a preview, which leaves the order database alone.
+		o.db = ""
+		switch o.to {
+		case "ynab":
+			return runPreview(ctx, fs, o)
+		case "actual", "firefly":
+			return previewOffline(ctx, fs, o)
+		}
+		return fmt.Errorf("unknown budgeting tool %q", o.to)
+	}
+	if err := require(fs, "orders"); err != nil {
+		return err
+	}
+	s, err := o.newSink(ctx, fs)
+	if err != nil {
+		return err
+	}
+	merged, err := o.loadMerged()
+	if err != nil {
+		return err
+	}
+	return s.Write(ctx, merged)
+}
+
+// runPreview prints the transactions an import would create.
+func runPreview(ctx context.Context, fs *flag.FlagSet, o *options) error {
+	if err := require(fs, "budget", "account", "orders"); err != nil {
+		return err
+	}
+	if err := o.resolveToken(); err != nil {
+		return err
+	}
+	_, _, txns, err := o.buildTransactions(ctx)
+	return printPreview(txns, err)
+}
+
+// previewOffline prints the transactions an import into another budgeting
+// tool would be built from. They are built without a YNAB budget, as for
+// export, so neither YNAB nor the tool is needed.
+func previewOffline(ctx context.Context, fs *flag.FlagSet, o *options) error {
+	if err := require(fs, "orders"); err != nil {
+		return err
+	}
+	merged, err := o.loadMerged()
+	if err != nil {
+		return err
+	}
+	txns, err := o.buildTxns(ctx, merged, nil, ptrOf(export.AccountID))
+	return printPreview(txns, err)
+}
+
+// printPreview prints built transactions as JSON, returning the build error.
+// Transactions that fail the checks are printed too, to help explain them.
+func printPreview(txns []*models.SaveTransaction, err error) error {
+	var cerr *txn.CheckError
+	if err != nil && !errors.As(err, &cerr) {
+		return err
+	}
+	j, jerr := json.MarshalIndent(&models.PostTransactionsWrapper{Transactions: txns}, "", "\t")
+	if jerr != nil {
+		return fmt.Errorf("json.MarshalIndent(): %w", jerr)
+	}
+	fmt.Println(string(j))
+	return err
+}
+
+// runExport writes the transactions an import would create to a file, without
+// using the YNAB API.
+func runExport(ctx context.Context, fs *flag.FlagSet, o *options) error {
+	if err := require(fs, "orders"); err != nil {
+		return err
+	}
+	format := flagString(fs, "format")
+	if _, ok := export.LedgerFormats[format]; !ok && export.Formats[format] == nil {
+		return fmt.Errorf("unknown export format %q", format)
+	}
+	src, err := orders.Lookup(o.source)
+	if err != nil {
+		return err
+	}
+	b, err := o.newBuilder(ctx, nil, ptrOf(export.AccountID))
+	if err != nil {
+		return err
+	}
+	b.GiftCardAccount = flagString(fs, "gift_card_account")
+	s := &sink.File{
+		Builder: b,
+		Format:  format,
+		Output:  flagString(fs, "output"),
+		Account: src.Retailer().Payee,
+		Payment: flagString(fs, "ledger_account"),
+		Expense: flagString(fs, "expense_account"),
+	}
+	merged, err := o.loadMerged()
+	if err != nil {
+		return err
+	}
+	return s.Write(ctx, merged)
+}
+
+// runMatch matches the transactions an import would create to existing
+// transactions in the account with the same amount and a nearby date.
+func runMatch(ctx context.Context, fs *flag.FlagSet, o *options) error {
+	if err := require(fs, "budget", "account", "orders"); err != nil {
+		return err
+	}
+	if err := o.resolveToken(); err != nil {
+		return err
+	}
+	days := flagInt(fs, "days")
+	// Matching doesn't post anything, so failed checks are only reported.
+	budgetID, accountID, txns, err := o.buildTransactions(ctx)
+	var cerr *txn.CheckError
+	if errors.As(err, &cerr) {
+		log.Print(err)
+	} else if err != nil {
+		return err
+	}
+
+	// Fetch existing transactions from shortly before the first order.
+	since := time.Time(*txns[0].Date)
+	for _, t := range txns {
+		if d := time.Time(*t.Date); d.Before(since) {
+			since = d
+		}
+	}
+	since = since.AddDate(0, 0, -days)
+	existing, err := o.ynabClient().AccountTransactions(ctx, *budgetID, *accountID, strfmt.Date(since))
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "DATE\tAMOUNT\tIMPORT ID\tSTATUS\tEXISTING")
+	used := make(map[string]bool)
+	for _, t := range txns {
+		status, match := "new", ynabsync.Match(t, existing, used, days)
+		desc := ""
+		if match != nil {
+			used[*match.ID] = true
+			status = "match"
+			if match.ImportID == t.ImportID {
+				status = "imported"
+			}
+			desc = fmt.Sprintf("%s %s %q", match.Date, match.PayeeName, match.Memo)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", t.Date, orders.FormatMoney(*t.Amount), t.ImportID, status, desc)
+	}
+	return w.Flush()
+}
+
+// runReconcile compares, per month, the Amazon charges assigned to an account
+// with the YNAB transactions in it carrying our import IDs or the retailer's
+// payee, and lists the unmatched ones on either side.
+func runReconcile(ctx context.Context, fs *flag.FlagSet, o *options) error {
+	if err := require(fs, "budget", "account", "orders"); err != nil {
+		return err
+	}
+	if err := o.resolveToken(); err != nil {
+		return err
+	}
+	s, err := orders.Lookup(o.source)
+	if err != nil {
+		return err
+	}
+	days := flagInt(fs, "days")
+	// Reconciling doesn't post anything, so failed checks are only reported.
+	budgetID, accountID, txns, err := o.buildTransactions(ctx)
+	var cerr *txn.CheckError
+	if errors.As(err, &cerr) {
+		log.Print(err)
+	} else if err != nil {
+		return err
+	}
+
+	// Rules may route some orders to other accounts.
+	var charges []*models.SaveTransaction
+	for _, t := range txns {
+		if t.AccountID != nil && *t.AccountID == *accountID {
+			charges = append(charges, t)
+		}
+	}
+	if len(charges) == 0 {
+		return fmt.Errorf("no Amazon charges assigned to the account")
+	}
+	all, err := o.ynabClient().AccountTransactions(ctx, *budgetID, *accountID, reconcile.Since(charges, days))
+	if err != nil {
+		return err
+	}
+	return reconcile.New(charges, all, s.Retailer(), days).WriteText(os.Stdout)
+}
+
+// runReport reports how Amazon orders and items were merged.
+func runReport(ctx context.Context, fs *flag.FlagSet, o *options) error {
+	if err := require(fs, "orders"); err != nil {
+		return err
+	}
+	_, report, err := o.loadOrders()
+	if err != nil {
+		return err
+	}
+	switch format := flagString(fs, "format"); format {
+	case "text":
+		return report.WriteText(os.Stdout)
+	case "json":
+		return report.WriteJSON(os.Stdout)
+	default:
+		return fmt.Errorf("unknown report format %q", format)
+	}
+}
+
+// spendingFormats are the spending report formats.
+var spendingFormats = map[string]func(*spending.Report, io.Writer) error{
+	"text": (*spending.Report).WriteText,
+	"csv":  (*spending.Report).WriteCSV,
+	"html": (*spending.Report).WriteHTML,
+}
+
+// runSpending reports spending by month, seller, category rule and payment
+// instrument.
+func runSpending(ctx context.Context, fs *flag.FlagSet, o *options) error {
+	if err := require(fs, "orders"); err != nil {
+		return err
+	}
+	format := flagString(fs, "format")
+	write := spendingFormats[format]
+	if write == nil {
+		return fmt.Errorf("unknown report format %q", format)
+	}
+	rules, err := txn.LoadRules(o.rules)
+	if err != nil {
+		return err
+	}
+	merged, err := o.loadMerged()
+	if err != nil {
+		return err
+	}
+	r := spending.New(merged, rules, flagInt(fs, "top"))
+	return export.WriteFile(flagString(fs, "output"), func(w io.Writer) error { return write(r, w) })
+}
+
+// runUndo deletes the transactions created by a journal run, or lists the
+// journal runs if no run is given.
+func runUndo(ctx context.Context, fs *flag.FlagSet, o *options) error {
+	if o.journal == "" {
+		return fmt.Errorf("the journal is disabled")
+	}
+	j := &journal.Journal{Dir: o.journal, Logf: log.Printf}
+	run := fs.Arg(0)
+	if run == "" {
+		entries, err := j.List()
+		if err != nil {
+			return err
+		}
+		printJournal(os.Stdout, entries)
+		return nil
+	}
+	if err := o.resolveToken(); err != nil {
+		return err
+	}
+	n, err := j.Undo(ctx, run, o.ynabClient())
+	if n > 0 || err == nil {
+		log.Printf("deleted %d transactions from run %s", n, run)
+	}
+	return err
+}
+
+// runToken reports which source the token comes from, or stores or deletes
+// the token in the OS secret store.
+func runToken(ctx context.Context, fs *flag.FlagSet, o *options) error {
+	switch fs.Arg(0) {
+	case "":
+		token, source, err := o.readToken()
+		if err != nil {
+			return err
+		}
+		if token == "" {
+			source = "the -token flag"
+		}
+		if err := o.resolveToken(); err != nil {
+			return err
+		}
+		fmt.Printf("token from %s\n", source)
+		return nil
+	case "set":
+		if err := require(fs, "token_keyring"); err != nil {
+			return err
+		}
+		return storeToken(o.tokenKeyring)
+	case "delete":
+		if err := require(fs, "token_keyring"); err != nil {
+			return err
+		}
+		if err := keyring.Delete(keyringService, o.tokenKeyring); err != nil {
+			return fmt.Errorf("keyring.Delete(%q): %w", o.tokenKeyring, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown token command %q", fs.Arg(0))
+	}
+}
+
+// runConfig lists the profiles of the config file, or validates them. With a
+// profile flag only that profile is validated.
+func runConfig(ctx context.Context, fs *flag.FlagSet, o *options) error {
+	c, err := loadConfig(o.config)
+	if err != nil {
+		return err
+	}
+	switch fs.Arg(0) {
+	case "":
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "PROFILE\tBUDGET\tACCOUNT\tDEFAULT")
+		for _, name := range c.profileNames() {
+			p := c.Profiles[name]
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, p.Budget, p.Account, yesNo(name == c.Default))
+		}
+		return w.Flush()
+	case "validate":
+		names := c.profileNames()
+		if o.profile != "" {
+			names = []string{o.profile}
+		}
+		failed := 0
+		for _, name := range names {
+			if err := validateProfile(ctx, o.config, name); err != nil {
+				log.Printf("profile %q: %v", name, err)
+				failed++
+				continue
+			}
+			log.Printf("profile %q: ok", name)
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d profiles are invalid", failed, len(names))
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown config command %q", fs.Arg(0))
+	}
+}
+
+// yesNo formats a bool for tables.
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// usage prints the list of commands.
+func usage() {
+	w := flag.CommandLine.Output()
+	fmt.Fprintf(w, "usage: %s <command> [flags] [args]\n\nCommands:\n", progName)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, c := range commands {
+		fmt.Fprintf(tw, "  %s\t%s\n", strings.TrimSpace(c.name+" "+c.args), c.short)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\nRun \"%s help <command>\" for the flags of a command, e.g. \"%[1]s help report spending\".\n", progName)
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// config is a JSON file of named profiles.
+type config struct {
+	// Default names the profile used when no profile flag is given.
+	Default string `json:"default,omitempty"`
+
+	Profiles map[string]*profile `json:"profiles"`
+}
+
+// profile provides default flag values. Relative paths are relative to the
+// config file, except orders, items and invoices, which are relative to the input
+// directory if there is one.
+type profile struct {
+	// TokenEnv, TokenFile, TokenCommand and TokenKeyring are the token
+	// sources; see the flags of the same names.
+	TokenEnv     string `json:"token_env,omitempty"`
+	TokenFile    string `json:"token_file,omitempty"`
+	TokenCommand string `json:"token_command,omitempty"`
+	TokenKeyring string `json:"token_keyring,omitempty"`
+
+	Budget  string `json:"budget,omitempty"`
+	Account string `json:"account,omitempty"`
+
+	Source      string `json:"source,omitempty"`
+	InputDir    string `json:"input_dir,omitempty"`
+	Orders      string `json:"orders,omitempty"`
+	Items       string `json:"items,omitempty"`
+	Invoices    string `json:"invoices,omitempty"`
+	Diagnostics string `json:"diagnostics,omitempty"`
+
+	Color             string `json:"color,omitempty"`
+	Cleared           string `json:"cleared,omitempty"`
+	Approve           *bool  `json:"approve,omitempty"`
+	Rules             string `json:"rules,omitempty"`
+	Payees            string `json:"payees,omitempty"`
+	MemoTemplate      string `json:"memo_template,omitempty"`
+	SplitMemoTemplate string `json:"split_memo_template,omitempty"`
+	PayeeTemplate     string `json:"payee_template,omitempty"`
+	Groceries         string `json:"groceries,omitempty"`
+
+	BatchSize  *int   `json:"batch_size,omitempty"`
+	MaxRetries *int   `json:"max_retries,omitempty"`
+	Checkpoint string `json:"checkpoint,omitempty"`
+	Journal    string `json:"journal,omitempty"`
+	DB         string `json:"db,omitempty"`
+}
+
+// defaultConfigFile returns the default config file name.
+func defaultConfigFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "config.json"
+	}
+	return filepath.Join(dir, "ynab-amazon-import", "config.json")
+}
+
+// configFlags registers the config file and profile flags.
+func (o *options) configFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.config, "config", defaultConfigFile(), "JSON config file of named profiles")
+	fs.StringVar(&o.profile, "profile", "", "Config profile providing default flag values")
+}
+
+// loadConfig loads a config file.
+func loadConfig(name string) (*config, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile(%q): %w", name, err)
+	}
+	c := &config{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.DisallowUnknownFields()
+	if err := d.Decode(c); err != nil {
+		return nil, fmt.Errorf("failed to parse config %q: %w", name, err)
+	}
+	if c.Default != "" && c.Profiles[c.Default] == nil {
+		return nil, fmt.Errorf("default profile %q not found in config %q", c.Default, name)
+	}
+	return c, nil
+}
+
+// profileNames returns the profile names in order.
+func (c *config) profileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for n := range c.Profiles {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// values returns the flag values provided by a profile.
+func (p *profile) values(dir string) map[string]string {
+	path := func(name string) string {
+		if name == "" || filepath.IsAbs(name) {
+			return name
+		}
+		return filepath.Join(dir, name)
+	}
+	inputDir := path(p.InputDir)
+	input := func(name string) string {
+		if name == "" || filepath.IsAbs(name) {
+			return name
+		}
+		if inputDir != "" {
+			return filepath.Join(inputDir, name)
+		}
+		return path(name)
+	}
+
+	v := map[string]string{
+		"token_env":           p.TokenEnv,
+		"token_file":          path(p.TokenFile),
+		"token_command":       p.TokenCommand,
+		"token_keyring":       p.TokenKeyring,
+		"budget":              p.Budget,
+		"account":             p.Account,
+		"source":              p.Source,
+		"orders":              input(p.Orders),
+		"items":               input(p.Items),
+		"invoices":            input(p.Invoices),
+		"diagnostics":         path(p.Diagnostics),
+		"color":               p.Color,
+		"cleared":             p.Cleared,
+		"rules":               path(p.Rules),
+		"payees":              path(p.Payees),
+		"memo_template":       p.MemoTemplate,
+		"split_memo_template": p.SplitMemoTemplate,
+		"payee_template":      p.PayeeTemplate,
+		"groceries":           p.Groceries,
+		"checkpoint":          path(p.Checkpoint),
+		"journal":             path(p.Journal),
+		"db":                  path(p.DB),
+	}
+	if p.Approve != nil {
+		v["approve"] = strconv.FormatBool(*p.Approve)
+	}
+	if p.BatchSize != nil {
+		v["batch_size"] = strconv.Itoa(*p.BatchSize)
+	}
+	if p.MaxRetries != nil {
+		v["max_retries"] = strconv.Itoa(*p.MaxRetries)
+	}
+	for k, s := range v {
+		if s == "" {
+			delete(v, k)
+		}
+	}
+	return v
+}
+
+// applyConfig sets the flags that weren't given on the command line from the
+// selected profile. A missing default config file is ignored unless a profile
+// was asked for.
+func (o *options) applyConfig(fs *flag.FlagSet) error {
+	if o.config == "" {
+		return nil
+	}
+	c, err := loadConfig(o.config)
+	if errors.Is(err, os.ErrNotExist) && o.config == defaultConfigFile() && o.profile == "" {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	name := o.profile
+	if name == "" {
+		name = c.Default
+	}
+	if name == "" {
+		return nil
+	}
+	p := c.Profiles[name]
+	if p == nil {
+		return fmt.Errorf("profile %q not found in config %q", name, o.config)
+	}
+
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	for k, v := range p.values(filepath.Dir(o.config)) {
+		if set[k] || fs.Lookup(k) == nil {
+			continue
+		}
+		if err := fs.Set(k, v); err != nil {
+			return fmt.Errorf("invalid %s in profile %q: %w", k, name, err)
+		}
+	}
+	return nil
+}
+
+// validateProfile checks that a profile's budget and account still exist and
+// that its rules, payee policy and templates load.
+func validateProfile(ctx context.Context, configName, name string) error {
+	o := &options{}
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	o.configFlags(fs)
+	o.tokenFlags(fs)
+	o.budgetFlags(fs, true)
+	o.cacheFlags(fs)
+	o.inputFlags(fs, true)
+	o.buildFlags(fs)
+	o.postFlags(fs)
+	o.journalFlags(fs)
+	if err := fs.Parse([]string{"-config", configName, "-profile", name}); err != nil {
+		return err
+	}
+	if err := o.applyConfig(fs); err != nil {
+		return err
+	}
+	if err := require(fs, "budget", "account"); err != nil {
+		return err
+	}
+	if err := o.resolveToken(); err != nil {
+		return err
+	}
+	for _, input := range []string{o.orders, o.items, o.invoices} {
+		if input == "" {
+			continue
+		}
+		if _, err := os.Stat(input); err != nil {
+			return fmt.Errorf("os.Stat(%q): %w", input, err)
+		}
+	}
+	budgetID, accountID, err := budgetAccount(ctx, o.budget, o.account, o.metaCache())
+	if err != nil {
+		return err
+	}
+	_, err = o.newBuilder(ctx, budgetID, accountID)
+	return err
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dbinit/ynab-amazon-import/orderdb"
+	"github.com/dbinit/ynab-amazon-import/orders"
+	"github.com/dbinit/ynab-amazon-import/ynabsync"
+)
+
+// defaultDBFile returns the default order database file.
+func defaultDBFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "orders.json"
+	}
+	return filepath.Join(dir, "ynab-amazon-import", "orders.json")
+}
+
+// dbFlags registers the order database flag.
+func (o *options) dbFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.db, "db", defaultDBFile(), "Order database file recording every order read and the YNAB transactions created for it, or empty to disable it")
+}
+
+// recordOrders adds merged orders to the order database, if it's enabled.
+// Failing to record them is logged rather than failing the run, as for the
+// journal.
+func (o *options) recordOrders(merged map[string]*orders.Order, report *orders.MergeReport) {
+	if o.db == "" {
+		return
+	}
+	err := updateDB(o.db, func(db *orderdb.DB) {
+		added, updated := db.Add(o.source, merged, report, time.Now())
+		log.Printf("order database: %d orders added, %d updated", added, updated)
+	})
+	if err != nil {
+		log.Printf("failed to record orders: %v", err)
+	}
+}
+
+// recordTransactions links the YNAB transactions created in a budget to their
+// orders in the order database, if it's enabled.
+func (o *options) recordTransactions(budgetID string, created []*ynabsync.Snapshot) {
+	if o.db == "" || len(created) == 0 {
+		return
+	}
+	err := updateDB(o.db, func(db *orderdb.DB) {
+		log.Printf("order database: %d transactions linked", db.Link(budgetID, created, time.Now()))
+	})
+	if err != nil {
+		log.Printf("failed to record transactions: %v", err)
+	}
+}
+
+// updateDB locks the order database, opens it, updates it and saves it.
+func updateDB(name string, update func(db *orderdb.DB)) (err error) {
+	unlock, err := orderdb.Lock(name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if uerr := unlock(); uerr != nil && err == nil {
+			err = uerr
+		}
+	}()
+	db, err := orderdb.Open(name)
+	if err != nil {
+		return err
+	}
+	update(db)
+	return db.Save()
+}
+
+// runQuery looks up orders in the order database.
+func runQuery(ctx context.Context, fs *flag.FlagSet, o *options) error {
+	if err := require(fs, "db"); err != nil {
+		return err
+	}
+	q := orderdb.Query{
+		OrderID: fs.Arg(0),
+		Title:   flagString(fs, "title"),
+		ASIN:    flagString(fs, "asin"),
+	}
+	if v := flagString(fs, "amount"); v != "" {
+		n, err := orders.ParseMoney(v, false)
+		if err != nil {
+			return err
+		}
+		q.Amount = n
+	}
+	if q == (orderdb.Query{}) {
+		return fmt.Errorf("give an order ID, or one of -title, -asin or -amount")
+	}
+	db, err := orderdb.Open(o.db)
+	if err != nil {
+		return err
+	}
+	rs := db.Find(q)
+
+	switch format := flagString(fs, "format"); format {
+	case "json":
+		j, err := json.MarshalIndent(rs, "", "\t")
+		if err != nil {
+			return fmt.Errorf("json.MarshalIndent(): %w", err)
+		}
+		fmt.Println(string(j))
+		return nil
+	case "text":
+	default:
+		return fmt.Errorf("unknown query format %q", format)
+	}
+	if len(rs) == 0 {
+		return fmt.Errorf("no orders found")
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SHIPPED\tORDER ID\tAMOUNT\tDESCRIPTION\tYNAB TRANSACTIONS")
+	for _, r := range rs {
+		od := r.Order
+		var ids []string
+		for _, t := range r.Transactions {
+			ids = append(ids, t.ID)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", od.ShipmentDate, od.ID, orders.FormatMoney(od.TotalCharged), recordDescription(r), strings.Join(ids, " "))
+		for _, it := range od.Items {
+			mark := " "
+			if (q.Title != "" || q.ASIN != "") && q.MatchItem(it) {
+				mark = "*"
+			}
+			title := it.Title
+			if it.Quantity > 1 {
+				title = fmt.Sprintf("%dx %s", it.Quantity, title)
+			}
+			if it.Seller != "" {
+				title += " (" + it.Seller + ")"
+			}
+			fmt.Fprintf(w, "\t\t%s\t%s %s\t\n", orders.FormatMoney(it.Total), mark, title)
+		}
+		for _, f := range od.Fees {
+			fmt.Fprintf(w, "\t\t%s\t  %s\t\n", orders.FormatMoney(f.Amount), f.Memo)
+		}
+		for _, p := range od.Payments {
+			fmt.Fprintf(w, "\t\t%s\t  paid with %s\t\n", orders.FormatMoney(p.Amount), p.Instrument)
+		}
+		for _, d := range r.Diagnostics {
+			fmt.Fprintf(w, "\t\t\t  %s: %s\t\n", d.Kind, d.Detail)
+		}
+	}
+	return w.Flush()
+}
+
+// recordDescription describes a record's source and purchasing details.
+func recordDescription(r *orderdb.Record) string {
+	parts := []string{r.Source}
+	if r.Order.PONumber != "" {
+		parts = append(parts, "PO "+r.Order.PONumber)
+	}
+	if r.Order.Buyer != "" {
+		parts = append(parts, "bought by "+r.Order.Buyer)
+	}
+	return strings.Join(parts, ", ")
+}
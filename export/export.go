@@ -0,0 +1,204 @@
+// Package export writes transactions to files for YNAB's file import, as
+// CSV, QIF or OFX, and tool-neutral entries to plain-text accounting journals
+// for ledger, hledger and beancount.
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dbinit/ynab-amazon-import/models"
+	"github.com/dbinit/ynab-amazon-import/orders"
+	"github.com/go-openapi/strfmt"
+)
+
+// AccountID is the placeholder account ID of exported transactions, which
+// aren't posted to an account.
+const AccountID = strfmt.UUID("00000000-0000-0000-0000-000000000000")
+
+// Formats maps the transaction file formats to their writers. The account
+// names the statement account for formats that have one.
+var Formats = map[string]func(w io.Writer, txns []*models.SaveTransaction, account string) error{
+	"csv": func(w io.Writer, txns []*models.SaveTransaction, _ string) error { return WriteCSV(w, txns) },
+	"qif": func(w io.Writer, txns []*models.SaveTransaction, _ string) error { return WriteQIF(w, txns) },
+	"ofx": WriteOFX,
+}
+
+// WriteFile calls write with a file. An empty name or "-" writes to stdout.
+func WriteFile(name string, write func(io.Writer) error) (err error) {
+	if name == "" || name == "-" {
+		return write(os.Stdout)
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("os.Create(%q): %w", name, err)
+	}
+	defer func() {
+		if ferr := f.Close(); ferr != nil && err == nil {
+			err = fmt.Errorf("(os.File).Close(%q): %w", name, ferr)
+		}
+	}()
+	return write(f)
+}
+
+// Decimal formats milliunits as a decimal amount, e.g. "-12.34".
+func Decimal(amount int64) string {
+	return strings.Replace(orders.FormatMoney(amount), "$", "", 1)
+}
+
+// PayeeName returns the payee name of a transaction, or its payee ID if it
+// was mapped to an existing payee.
+func PayeeName(t *models.SaveTransaction) string {
+	if t.PayeeName != "" {
+		return t.PayeeName
+	}
+	return t.PayeeID.String()
+}
+
+// WriteCSV writes transactions in the CSV layout of YNAB's file import. CSV
+// has no split lines, so split transactions are written whole.
+func WriteCSV(w io.Writer, txns []*models.SaveTransaction) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"Date", "Payee", "Memo", "Outflow", "Inflow"}); err != nil {
+		return fmt.Errorf("(*csv.Writer).Write(): %w", err)
+	}
+	for _, t := range txns {
+		var outflow, inflow string
+		if *t.Amount < 0 {
+			outflow = Decimal(-*t.Amount)
+		} else {
+			inflow = Decimal(*t.Amount)
+		}
+		date := time.Time(*t.Date).Format("01/02/2006")
+		if err := cw.Write([]string{date, PayeeName(t), t.Memo, outflow, inflow}); err != nil {
+			return fmt.Errorf("(*csv.Writer).Write(): %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("(*csv.Writer).Flush(): %w", err)
+	}
+	return nil
+}
+
+// WriteQIF writes transactions as a QIF bank account, with split lines for
+// split transactions.
+func WriteQIF(w io.Writer, txns []*models.SaveTransaction) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "!Type:Bank")
+	for _, t := range txns {
+		fmt.Fprintf(bw, "D%s\n", time.Time(*t.Date).Format("01/02/2006"))
+		fmt.Fprintf(bw, "T%s\n", Decimal(*t.Amount))
+		if p := PayeeName(t); p != "" {
+			fmt.Fprintf(bw, "P%s\n", oneLine(p))
+		}
+		if t.Memo != "" {
+			fmt.Fprintf(bw, "M%s\n", oneLine(t.Memo))
+		}
+		if t.Cleared != models.SaveTransactionWithOptionalFieldsClearedUncleared {
+			fmt.Fprintln(bw, "C*")
+		}
+		for _, st := range t.Subtransactions {
+			// Categories are IDs, which QIF has no place for, so splits only
+			// carry the memo and amount.
+			fmt.Fprintln(bw, "S")
+			if st.Memo != "" {
+				fmt.Fprintf(bw, "E%s\n", oneLine(st.Memo))
+			}
+			fmt.Fprintf(bw, "$%s\n", Decimal(*st.Amount))
+		}
+		fmt.Fprintln(bw, "^")
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("(*bufio.Writer).Flush(): %w", err)
+	}
+	return nil
+}
+
+// oneLine replaces line breaks, which end QIF fields and plain-text
+// accounting comments.
+func oneLine(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
+
+// WriteOFX writes transactions as an OFX 1.02 credit card statement. OFX has
+// no split lines, so split transactions are written whole. The import IDs are
+// the transaction IDs, so YNAB skips transactions it has already imported.
+// The account is the statement's account ID, e.g. the retailer.
+func WriteOFX(w io.Writer, txns []*models.SaveTransaction, account string) error {
+	start, end := time.Time(*txns[0].Date), time.Time(*txns[0].Date)
+	var balance int64
+	for _, t := range txns {
+		if d := time.Time(*t.Date); d.Before(start) {
+			start = d
+		} else if d.After(end) {
+			end = d
+		}
+		balance += *t.Amount
+	}
+	const ofxDate = "20060102"
+	now := time.Now().UTC().Format("20060102150405")
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprint(bw, "OFXHEADER:100\r\nDATA:OFXSGML\r\nVERSION:102\r\nSECURITY:NONE\r\nENCODING:USASCII\r\nCHARSET:1252\r\nCOMPRESSION:NONE\r\nOLDFILEUID:NONE\r\nNEWFILEUID:NONE\r\n\r\n")
+	fmt.Fprintln(bw, "<OFX>")
+	fmt.Fprintln(bw, "<SIGNONMSGSRSV1><SONRS>")
+	fmt.Fprintln(bw, "<STATUS><CODE>0<SEVERITY>INFO</STATUS>")
+	fmt.Fprintf(bw, "<DTSERVER>%s\n", now)
+	fmt.Fprintln(bw, "<LANGUAGE>ENG")
+	fmt.Fprintln(bw, "</SONRS></SIGNONMSGSRSV1>")
+	fmt.Fprintln(bw, "<CREDITCARDMSGSRSV1><CCSTMTTRNRS>")
+	fmt.Fprintln(bw, "<TRNUID>0")
+	fmt.Fprintln(bw, "<STATUS><CODE>0<SEVERITY>INFO</STATUS>")
+	fmt.Fprintln(bw, "<CCSTMTRS>")
+	fmt.Fprintln(bw, "<CURDEF>USD")
+	fmt.Fprintf(bw, "<CCACCTFROM><ACCTID>%s</CCACCTFROM>\n", ofxText(account))
+	fmt.Fprintln(bw, "<BANKTRANLIST>")
+	fmt.Fprintf(bw, "<DTSTART>%s\n", start.Format(ofxDate))
+	fmt.Fprintf(bw, "<DTEND>%s\n", end.Format(ofxDate))
+	for _, t := range txns {
+		typ := "DEBIT"
+		if *t.Amount > 0 {
+			typ = "CREDIT"
+		}
+		fmt.Fprintln(bw, "<STMTTRN>")
+		fmt.Fprintf(bw, "<TRNTYPE>%s\n", typ)
+		fmt.Fprintf(bw, "<DTPOSTED>%s\n", time.Time(*t.Date).Format(ofxDate))
+		fmt.Fprintf(bw, "<TRNAMT>%s\n", Decimal(*t.Amount))
+		fmt.Fprintf(bw, "<FITID>%s\n", ofxText(t.ImportID))
+		// OFX limits names to 32 characters.
+		fmt.Fprintf(bw, "<NAME>%s\n", ofxText(truncate(PayeeName(t), 32)))
+		if t.Memo != "" {
+			fmt.Fprintf(bw, "<MEMO>%s\n", ofxText(t.Memo))
+		}
+		fmt.Fprintln(bw, "</STMTTRN>")
+	}
+	fmt.Fprintln(bw, "</BANKTRANLIST>")
+	fmt.Fprintf(bw, "<LEDGERBAL><BALAMT>%s<DTASOF>%s</LEDGERBAL>\n", Decimal(balance), now)
+	fmt.Fprintln(bw, "</CCSTMTRS>")
+	fmt.Fprintln(bw, "</CCSTMTTRNRS></CREDITCARDMSGSRSV1>")
+	fmt.Fprintln(bw, "</OFX>")
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("(*bufio.Writer).Flush(): %w", err)
+	}
+	return nil
+}
+
+// truncate as string to a maximum length.
+func truncate(s string, l int) string {
+	if utf8.RuneCountInString(s) <= l {
+		return s
+	}
+	return string([]rune(s)[:l])
+}
+
+// ofxText escapes OFX element text and removes line breaks.
+func ofxText(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", " ", "\n", " ").Replace(s)
+}
@@ -0,0 +1,85 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/dbinit/ynab-amazon-import/orders"
+	"github.com/dbinit/ynab-amazon-import/txn"
+)
+
+// LedgerFormats maps the plain-text accounting formats to their writers,
+// which post the order totals to the payment account.
+var LedgerFormats = map[string]func(w io.Writer, es []*txn.Entry, payment string) error{
+	"ledger": func(w io.Writer, es []*txn.Entry, payment string) error {
+		return WriteLedger(w, es, payment, "2006/01/02")
+	},
+	"hledger": func(w io.Writer, es []*txn.Entry, payment string) error {
+		return WriteLedger(w, es, payment, "2006-01-02")
+	},
+	"beancount": WriteBeancount,
+}
+
+// WriteLedger writes entries as a ledger or hledger journal, with the order
+// and import IDs as metadata tags.
+func WriteLedger(w io.Writer, entries []*txn.Entry, payment, dateFormat string) error {
+	bw := bufio.NewWriter(w)
+	for i, en := range entries {
+		if i > 0 {
+			fmt.Fprintln(bw)
+		}
+		status := "!"
+		if en.Cleared {
+			status = "*"
+		}
+		fmt.Fprintf(bw, "%s %s %s\n", en.Date.Format(dateFormat), status, oneLine(en.Payee))
+		if en.Memo != "" {
+			fmt.Fprintf(bw, "    ; %s\n", oneLine(en.Memo))
+		}
+		fmt.Fprintf(bw, "    ; order_id: %s\n", en.OrderID)
+		fmt.Fprintf(bw, "    ; import_id: %s\n", en.ImportID)
+		for _, p := range en.Postings {
+			line := fmt.Sprintf("    %-36s  %12s", p.Account, orders.FormatMoney(p.Amount))
+			if p.Memo != "" {
+				line += "  ; " + oneLine(p.Memo)
+			}
+			fmt.Fprintln(bw, line)
+		}
+		fmt.Fprintf(bw, "    %-36s  %12s\n", payment, orders.FormatMoney(en.Total))
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("(*bufio.Writer).Flush(): %w", err)
+	}
+	return nil
+}
+
+// WriteBeancount writes entries as beancount transactions, with the order and
+// import IDs as metadata. The accounts must be opened elsewhere.
+func WriteBeancount(w io.Writer, entries []*txn.Entry, payment string) error {
+	bw := bufio.NewWriter(w)
+	for i, en := range entries {
+		if i > 0 {
+			fmt.Fprintln(bw)
+		}
+		flag := "!"
+		if en.Cleared {
+			flag = "*"
+		}
+		fmt.Fprintf(bw, "%s %s %s %s\n", en.Date.Format("2006-01-02"), flag, strconv.Quote(oneLine(en.Payee)), strconv.Quote(oneLine(en.Memo)))
+		fmt.Fprintf(bw, "  order_id: %s\n", strconv.Quote(en.OrderID))
+		fmt.Fprintf(bw, "  import_id: %s\n", strconv.Quote(en.ImportID))
+		for _, p := range en.Postings {
+			fmt.Fprintf(bw, "  %-36s  %12s USD\n", p.Account, Decimal(p.Amount))
+			if p.Memo != "" {
+				fmt.Fprintf(bw, "    memo: %s\n", strconv.Quote(oneLine(p.Memo)))
+			}
+		}
+		fmt.Fprintf(bw, "  %-36s  %12s USD\n", payment, Decimal(en.Total))
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("(*bufio.Writer).Flush(): %w", err)
+	}
+	return nil
+}
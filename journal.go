@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dbinit/ynab-amazon-import/journal"
+)
+
+// defaultJournalDir returns the default journal directory.
+func defaultJournalDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "journal"
+	}
+	return filepath.Join(dir, "ynab-amazon-import", "journal")
+}
+
+// setFlags returns the flags set for a run, except the token, for its
+// journal entry.
+func setFlags(fs *flag.FlagSet) map[string]string {
+	flags := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name != "token" {
+			flags[f.Name] = f.Value.String()
+		}
+	})
+	return flags
+}
+
+// printJournal writes a line for each journal entry.
+func printJournal(w io.Writer, entries []*journal.Entry) {
+	for _, e := range entries {
+		status := ""
+		if e.Undone != nil {
+			status = " (undone " + e.Undone.Format(time.RFC3339) + ")"
+		} else if e.Error != "" {
+			status = " (failed)"
+		}
+		fmt.Fprintf(w, "%s\t%d transactions\tbudget %s\taccount %s%s\n",
+			e.Run, len(e.Transactions), e.BudgetID, e.AccountID, status)
+	}
+}
@@ -0,0 +1,262 @@
+// Package journal records import runs, with the inputs, flags and created
+// transactions of each, so a run can be undone by deleting its transactions.
+package journal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dbinit/ynab-amazon-import/models"
+	"github.com/dbinit/ynab-amazon-import/ynabsync"
+)
+
+// Run ID format.
+const runIDFormat = "20060102T150405Z"
+
+// Journal is a directory of journal entries, one file per run.
+type Journal struct {
+	Dir string
+
+	// Logf logs transactions that are already gone when undoing a run, if it
+	// isn't nil.
+	Logf func(format string, args ...any)
+}
+
+// Entry records a single import run.
+type Entry struct {
+	Run       string    `json:"run"`
+	Time      time.Time `json:"time"`
+	BudgetID  string    `json:"budget_id"`
+	AccountID string    `json:"account_id"`
+
+	// Inputs maps input file names to SHA-256 hashes of their contents. Input
+	// folders are hashed by the names and contents of their files.
+	Inputs map[string]string `json:"inputs"`
+
+	// Flags records the flags set for the run, except the token.
+	Flags map[string]string `json:"flags"`
+
+	ynabsync.Result
+
+	// Error is set if the run failed part way through.
+	Error string `json:"error,omitempty"`
+
+	// Undone is set once the run's transactions have been deleted.
+	Undone *time.Time `json:"undone,omitempty"`
+}
+
+// NewEntry starts an entry for an import run with the flags that were set,
+// hashing the input files. Empty input names are skipped.
+func NewEntry(budgetID, accountID string, flags map[string]string, inputs ...string) (*Entry, error) {
+	now := time.Now().UTC()
+	e := &Entry{
+		Run:       now.Format(runIDFormat),
+		Time:      now,
+		BudgetID:  budgetID,
+		AccountID: accountID,
+		Inputs:    make(map[string]string),
+		Flags:     flags,
+	}
+	for _, name := range inputs {
+		if name == "" {
+			continue
+		}
+		sum, err := hashFile(name)
+		if err != nil {
+			return nil, err
+		}
+		e.Inputs[name] = sum
+	}
+	return e, nil
+}
+
+// hashFile returns the hex SHA-256 hash of a file, or of the relative names and
+// contents of the files in a folder.
+func hashFile(name string) (string, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(name, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("filepath.WalkDir(%q): %w", name, err)
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if path != name {
+			rel, err := filepath.Rel(name, path)
+			if err != nil {
+				return fmt.Errorf("filepath.Rel(%q, %q): %w", name, path, err)
+			}
+			fmt.Fprintf(h, "%s\x00", filepath.ToSlash(rel))
+		}
+		return copyFile(h, path)
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// copyFile copies the contents of a file to w.
+func copyFile(w io.Writer, name string) (err error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return fmt.Errorf("os.Open(%q): %w", name, err)
+	}
+	defer func() {
+		if ferr := f.Close(); ferr != nil && err == nil {
+			err = fmt.Errorf("(os.File).Close(%q): %w", name, ferr)
+		}
+	}()
+	if _, err := io.Copy(w, f); err != nil {
+		return fmt.Errorf("io.Copy(%q): %w", name, err)
+	}
+	return nil
+}
+
+// Create writes a new entry to the journal. Runs started in the same second
+// get a numbered suffix, e.g. "20240102T150405Z-2", so one never replaces
+// another's entry.
+func (j *Journal) Create(e *Entry) error {
+	if err := os.MkdirAll(j.Dir, 0o700); err != nil {
+		return fmt.Errorf("os.MkdirAll(%q): %w", j.Dir, err)
+	}
+	base := e.Run
+	for n := 2; ; n++ {
+		b, err := json.MarshalIndent(e, "", "\t")
+		if err != nil {
+			return fmt.Errorf("json.MarshalIndent(): %w", err)
+		}
+		name := filepath.Join(j.Dir, e.Run+".json")
+		f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, fs.ErrExist) {
+			e.Run = fmt.Sprintf("%s-%d", base, n)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("os.OpenFile(%q): %w", name, err)
+		}
+		_, err = f.Write(b)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write %q: %w", name, err)
+		}
+		return nil
+	}
+}
+
+// Save rewrites an existing entry.
+func (j *Journal) Save(e *Entry) error {
+	b, err := json.MarshalIndent(e, "", "\t")
+	if err != nil {
+		return fmt.Errorf("json.MarshalIndent(): %w", err)
+	}
+	name := filepath.Join(j.Dir, e.Run+".json")
+	if err := os.WriteFile(name, b, 0o600); err != nil {
+		return fmt.Errorf("os.WriteFile(%q): %w", name, err)
+	}
+	return nil
+}
+
+// Load reads an entry by run ID.
+func (j *Journal) Load(run string) (*Entry, error) {
+	name := filepath.Join(j.Dir, run+".json")
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile(%q): %w", name, err)
+	}
+	e := &Entry{}
+	if err := json.Unmarshal(b, e); err != nil {
+		return nil, fmt.Errorf("failed to parse journal entry %q: %w", name, err)
+	}
+	return e, nil
+}
+
+// List returns the entries in run order.
+func (j *Journal) List() ([]*Entry, error) {
+	names, err := filepath.Glob(filepath.Join(j.Dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("filepath.Glob(%q): %w", j.Dir, err)
+	}
+	sort.Strings(names)
+	var entries []*Entry
+	for _, name := range names {
+		e, err := j.Load(strings.TrimSuffix(filepath.Base(name), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// logf logs with Logf, if it is set.
+func (j *Journal) logf(format string, args ...any) {
+	if j.Logf != nil {
+		j.Logf(format, args...)
+	}
+}
+
+// Undo deletes the transactions created by a run, returning how many were
+// deleted. It refuses to delete anything if any of the transactions have been
+// reconciled or edited since.
+func (j *Journal) Undo(ctx context.Context, run string, c *ynabsync.Client) (int, error) {
+	e, err := j.Load(run)
+	if err != nil {
+		return 0, err
+	}
+	if e.Undone != nil {
+		return 0, fmt.Errorf("run %s was already undone at %s", run, e.Undone.Format(time.RFC3339))
+	}
+
+	// Check every transaction before deleting any.
+	var problems []string
+	var remaining []*ynabsync.Snapshot
+	for _, s := range e.Transactions {
+		t, err := c.Transaction(ctx, e.BudgetID, s.ID)
+		if errors.Is(err, ynabsync.ErrNotFound) {
+			j.logf("transaction %s no longer exists", s.ID)
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		if t.Deleted != nil && *t.Deleted {
+			j.logf("transaction %s was already deleted", s.ID)
+			continue
+		}
+		if t.Cleared != nil && *t.Cleared == models.TransactionSummaryClearedReconciled {
+			problems = append(problems, fmt.Sprintf("%s (%s) is reconciled", s.ID, s.ImportID))
+			continue
+		}
+		if changes := s.Changes(t); len(changes) > 0 {
+			problems = append(problems, fmt.Sprintf("%s (%s) was edited: %s", s.ID, s.ImportID, strings.Join(changes, ", ")))
+			continue
+		}
+		remaining = append(remaining, s)
+	}
+	if len(problems) > 0 {
+		return 0, fmt.Errorf("refusing to undo run %s:\n\t%s", run, strings.Join(problems, "\n\t"))
+	}
+
+	for i, s := range remaining {
+		if err := c.DeleteTransaction(ctx, e.BudgetID, s.ID); err != nil {
+			return i, err
+		}
+	}
+	now := time.Now().UTC()
+	e.Undone = &now
+	return len(remaining), j.Save(e)
+}
@@ -0,0 +1,228 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+)
+
+// CategoriesResponse categories response
+//
+// swagger:model CategoriesResponse
+type CategoriesResponse struct {
+
+	// data
+	// Required: true
+	Data *CategoriesResponseData `json:"data"`
+}
+
+// Validate validates this categories response
+func (m *CategoriesResponse) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateData(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *CategoriesResponse) validateData(formats strfmt.Registry) error {
+
+	if err := validate.Required("data", "body", m.Data); err != nil {
+		return err
+	}
+
+	if m.Data != nil {
+		if err := m.Data.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("data")
+			} else if ce, ok := err.(*errors.CompositeError); ok {
+				return ce.ValidateName("data")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ContextValidate validate this categories response based on the context it is used
+func (m *CategoriesResponse) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.contextValidateData(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *CategoriesResponse) contextValidateData(ctx context.Context, formats strfmt.Registry) error {
+
+	if m.Data != nil {
+		if err := m.Data.ContextValidate(ctx, formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("data")
+			} else if ce, ok := err.(*errors.CompositeError); ok {
+				return ce.ValidateName("data")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *CategoriesResponse) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *CategoriesResponse) UnmarshalBinary(b []byte) error {
+	var res CategoriesResponse
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
+
+// CategoriesResponseData categories response data
+//
+// swagger:model CategoriesResponseData
+type CategoriesResponseData struct {
+
+	// category groups
+	// Required: true
+	CategoryGroups []*CategoryGroupWithCategories `json:"category_groups"`
+
+	// The knowledge of the server
+	// Required: true
+	ServerKnowledge *int64 `json:"server_knowledge"`
+}
+
+// Validate validates this categories response data
+func (m *CategoriesResponseData) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateCategoryGroups(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateServerKnowledge(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *CategoriesResponseData) validateCategoryGroups(formats strfmt.Registry) error {
+
+	if err := validate.Required("data"+"."+"category_groups", "body", m.CategoryGroups); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(m.CategoryGroups); i++ {
+		if swag.IsZero(m.CategoryGroups[i]) { // not required
+			continue
+		}
+
+		if m.CategoryGroups[i] != nil {
+			if err := m.CategoryGroups[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("data" + "." + "category_groups" + "." + strconv.Itoa(i))
+				} else if ce, ok := err.(*errors.CompositeError); ok {
+					return ce.ValidateName("data" + "." + "category_groups" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+func (m *CategoriesResponseData) validateServerKnowledge(formats strfmt.Registry) error {
+
+	if err := validate.Required("data"+"."+"server_knowledge", "body", m.ServerKnowledge); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ContextValidate validate this categories response data based on the context it is used
+func (m *CategoriesResponseData) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.contextValidateCategoryGroups(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *CategoriesResponseData) contextValidateCategoryGroups(ctx context.Context, formats strfmt.Registry) error {
+
+	for i := 0; i < len(m.CategoryGroups); i++ {
+
+		if m.CategoryGroups[i] != nil {
+			if err := m.CategoryGroups[i].ContextValidate(ctx, formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("data" + "." + "category_groups" + "." + strconv.Itoa(i))
+				} else if ce, ok := err.(*errors.CompositeError); ok {
+					return ce.ValidateName("data" + "." + "category_groups" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *CategoriesResponseData) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *CategoriesResponseData) UnmarshalBinary(b []byte) error {
+	var res CategoriesResponseData
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
@@ -0,0 +1,350 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+)
+
+// Category category
+//
+// swagger:model Category
+type Category struct {
+
+	// Activity amount in milliunits format
+	// Required: true
+	Activity *int64 `json:"activity"`
+
+	// Balance in milliunits format
+	// Required: true
+	Balance *int64 `json:"balance"`
+
+	// Budgeted amount in milliunits format
+	// Required: true
+	Budgeted *int64 `json:"budgeted"`
+
+	// category group id
+	// Required: true
+	// Format: uuid
+	CategoryGroupID *strfmt.UUID `json:"category_group_id"`
+
+	// Whether or not the category has been deleted.  Deleted categories will only be included in delta requests.
+	// Required: true
+	Deleted *bool `json:"deleted"`
+
+	// The goal cadence
+	GoalCadence int32 `json:"goal_cadence,omitempty"`
+
+	// The goal cadence frequency
+	GoalCadenceFrequency int32 `json:"goal_cadence_frequency,omitempty"`
+
+	// The month a goal was created
+	// Format: date
+	GoalCreationMonth strfmt.Date `json:"goal_creation_month,omitempty"`
+
+	// The day of the goal
+	GoalDay int32 `json:"goal_day,omitempty"`
+
+	// The number of months, including the current month, left in the current goal period.
+	GoalMonthsToBudget int32 `json:"goal_months_to_budget,omitempty"`
+
+	// The total amount funded towards the goal within the current goal period.
+	GoalOverallFunded int64 `json:"goal_overall_funded,omitempty"`
+
+	// The amount of funding still needed to complete the goal within the current goal period.
+	GoalOverallLeft int64 `json:"goal_overall_left,omitempty"`
+
+	// The percentage completion of the goal
+	GoalPercentageComplete int32 `json:"goal_percentage_complete,omitempty"`
+
+	// The goal target amount in milliunits
+	GoalTarget int64 `json:"goal_target,omitempty"`
+
+	// The original target month for the goal to be completed.  Only some goal types specify this date.
+	// Format: date
+	GoalTargetMonth strfmt.Date `json:"goal_target_month,omitempty"`
+
+	// The type of goal, if the category has a goal (TB='Target Category Balance', TBD='Target Category Balance by Date', MF='Monthly Funding', NEED='Plan Your Spending')
+	// Enum: [TB TBD MF NEED DEBT]
+	GoalType *string `json:"goal_type,omitempty"`
+
+	// The amount of funding still needed in the current month to stay on track towards completing the goal within the current goal period.  This amount will generally correspond to the 'Underfunded' amount in the web and mobile clients except when viewing a category with a Needed for Spending Goal in a future month.  The web and mobile clients will ignore any funding from a prior goal period when viewing category with a Needed for Spending Goal in a future month.
+	GoalUnderFunded int64 `json:"goal_under_funded,omitempty"`
+
+	// Whether or not the category is hidden
+	// Required: true
+	Hidden *bool `json:"hidden"`
+
+	// id
+	// Required: true
+	// Format: uuid
+	ID *strfmt.UUID `json:"id"`
+
+	// name
+	// Required: true
+	Name *string `json:"name"`
+
+	// note
+	Note string `json:"note,omitempty"`
+
+	// If category is hidden this is the id of the category group it originally belonged to before it was hidden.
+	// Format: uuid
+	OriginalCategoryGroupID strfmt.UUID `json:"original_category_group_id,omitempty"`
+}
+
+// Validate validates this category
+func (m *Category) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateActivity(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateBalance(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateBudgeted(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateCategoryGroupID(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateDeleted(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateGoalCreationMonth(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateGoalTargetMonth(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateGoalType(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateHidden(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateID(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateName(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateOriginalCategoryGroupID(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *Category) validateActivity(formats strfmt.Registry) error {
+
+	if err := validate.Required("activity", "body", m.Activity); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Category) validateBalance(formats strfmt.Registry) error {
+
+	if err := validate.Required("balance", "body", m.Balance); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Category) validateBudgeted(formats strfmt.Registry) error {
+
+	if err := validate.Required("budgeted", "body", m.Budgeted); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Category) validateCategoryGroupID(formats strfmt.Registry) error {
+
+	if err := validate.Required("category_group_id", "body", m.CategoryGroupID); err != nil {
+		return err
+	}
+
+	if err := validate.FormatOf("category_group_id", "body", "uuid", m.CategoryGroupID.String(), formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Category) validateDeleted(formats strfmt.Registry) error {
+
+	if err := validate.Required("deleted", "body", m.Deleted); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Category) validateGoalCreationMonth(formats strfmt.Registry) error {
+	if swag.IsZero(m.GoalCreationMonth) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("goal_creation_month", "body", "date", m.GoalCreationMonth.String(), formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Category) validateGoalTargetMonth(formats strfmt.Registry) error {
+	if swag.IsZero(m.GoalTargetMonth) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("goal_target_month", "body", "date", m.GoalTargetMonth.String(), formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var categoryTypeGoalTypePropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["TB","TBD","MF","NEED","DEBT"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		categoryTypeGoalTypePropEnum = append(categoryTypeGoalTypePropEnum, v)
+	}
+}
+
+const (
+
+	// CategoryGoalTypeTB captures enum value "TB"
+	CategoryGoalTypeTB string = "TB"
+
+	// CategoryGoalTypeTBD captures enum value "TBD"
+	CategoryGoalTypeTBD string = "TBD"
+
+	// CategoryGoalTypeMF captures enum value "MF"
+	CategoryGoalTypeMF string = "MF"
+
+	// CategoryGoalTypeNEED captures enum value "NEED"
+	CategoryGoalTypeNEED string = "NEED"
+
+	// CategoryGoalTypeDEBT captures enum value "DEBT"
+	CategoryGoalTypeDEBT string = "DEBT"
+)
+
+// prop value enum
+func (m *Category) validateGoalTypeEnum(path, location string, value string) error {
+	if err := validate.EnumCase(path, location, value, categoryTypeGoalTypePropEnum, true); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *Category) validateGoalType(formats strfmt.Registry) error {
+	if swag.IsZero(m.GoalType) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateGoalTypeEnum("goal_type", "body", *m.GoalType); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Category) validateHidden(formats strfmt.Registry) error {
+
+	if err := validate.Required("hidden", "body", m.Hidden); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Category) validateID(formats strfmt.Registry) error {
+
+	if err := validate.Required("id", "body", m.ID); err != nil {
+		return err
+	}
+
+	if err := validate.FormatOf("id", "body", "uuid", m.ID.String(), formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Category) validateName(formats strfmt.Registry) error {
+
+	if err := validate.Required("name", "body", m.Name); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Category) validateOriginalCategoryGroupID(formats strfmt.Registry) error {
+	if swag.IsZero(m.OriginalCategoryGroupID) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("original_category_group_id", "body", "uuid", m.OriginalCategoryGroupID.String(), formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ContextValidate validates this category based on context it is used
+func (m *Category) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *Category) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *Category) UnmarshalBinary(b []byte) error {
+	var res Category
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
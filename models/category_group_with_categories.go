@@ -0,0 +1,175 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+)
+
+// CategoryGroupWithCategories category group with categories
+//
+// swagger:model CategoryGroupWithCategories
+type CategoryGroupWithCategories struct {
+	CategoryGroup
+
+	// Category group categories.  Amounts (budgeted, activity, balance, etc.) are specific to the current budget month (UTC).
+	// Required: true
+	Categories []*Category `json:"categories"`
+}
+
+// UnmarshalJSON unmarshals this object from a JSON structure
+func (m *CategoryGroupWithCategories) UnmarshalJSON(raw []byte) error {
+	// AO0
+	var aO0 CategoryGroup
+	if err := swag.ReadJSON(raw, &aO0); err != nil {
+		return err
+	}
+	m.CategoryGroup = aO0
+
+	// AO1
+	var dataAO1 struct {
+		Categories []*Category `json:"categories"`
+	}
+	if err := swag.ReadJSON(raw, &dataAO1); err != nil {
+		return err
+	}
+
+	m.Categories = dataAO1.Categories
+
+	return nil
+}
+
+// MarshalJSON marshals this object to a JSON structure
+func (m CategoryGroupWithCategories) MarshalJSON() ([]byte, error) {
+	_parts := make([][]byte, 0, 2)
+
+	aO0, err := swag.WriteJSON(m.CategoryGroup)
+	if err != nil {
+		return nil, err
+	}
+	_parts = append(_parts, aO0)
+	var dataAO1 struct {
+		Categories []*Category `json:"categories"`
+	}
+
+	dataAO1.Categories = m.Categories
+
+	jsonDataAO1, errAO1 := swag.WriteJSON(dataAO1)
+	if errAO1 != nil {
+		return nil, errAO1
+	}
+	_parts = append(_parts, jsonDataAO1)
+	return swag.ConcatJSON(_parts...), nil
+}
+
+// Validate validates this category group with categories
+func (m *CategoryGroupWithCategories) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	// validation for a type composition with CategoryGroup
+	if err := m.CategoryGroup.Validate(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateCategories(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *CategoryGroupWithCategories) validateCategories(formats strfmt.Registry) error {
+
+	if err := validate.Required("categories", "body", m.Categories); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(m.Categories); i++ {
+		if swag.IsZero(m.Categories[i]) { // not required
+			continue
+		}
+
+		if m.Categories[i] != nil {
+			if err := m.Categories[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("categories" + "." + strconv.Itoa(i))
+				} else if ce, ok := err.(*errors.CompositeError); ok {
+					return ce.ValidateName("categories" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+// ContextValidate validate this category group with categories based on the context it is used
+func (m *CategoryGroupWithCategories) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	var res []error
+
+	// validation for a type composition with CategoryGroup
+	if err := m.CategoryGroup.ContextValidate(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.contextValidateCategories(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *CategoryGroupWithCategories) contextValidateCategories(ctx context.Context, formats strfmt.Registry) error {
+
+	for i := 0; i < len(m.Categories); i++ {
+
+		if m.Categories[i] != nil {
+			if err := m.Categories[i].ContextValidate(ctx, formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("categories" + "." + strconv.Itoa(i))
+				} else if ce, ok := err.(*errors.CompositeError); ok {
+					return ce.ValidateName("categories" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *CategoryGroupWithCategories) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *CategoryGroupWithCategories) UnmarshalBinary(b []byte) error {
+	var res CategoryGroupWithCategories
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
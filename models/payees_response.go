@@ -0,0 +1,228 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+)
+
+// PayeesResponse payees response
+//
+// swagger:model PayeesResponse
+type PayeesResponse struct {
+
+	// data
+	// Required: true
+	Data *PayeesResponseData `json:"data"`
+}
+
+// Validate validates this payees response
+func (m *PayeesResponse) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateData(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *PayeesResponse) validateData(formats strfmt.Registry) error {
+
+	if err := validate.Required("data", "body", m.Data); err != nil {
+		return err
+	}
+
+	if m.Data != nil {
+		if err := m.Data.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("data")
+			} else if ce, ok := err.(*errors.CompositeError); ok {
+				return ce.ValidateName("data")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ContextValidate validate this payees response based on the context it is used
+func (m *PayeesResponse) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.contextValidateData(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *PayeesResponse) contextValidateData(ctx context.Context, formats strfmt.Registry) error {
+
+	if m.Data != nil {
+		if err := m.Data.ContextValidate(ctx, formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("data")
+			} else if ce, ok := err.(*errors.CompositeError); ok {
+				return ce.ValidateName("data")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *PayeesResponse) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *PayeesResponse) UnmarshalBinary(b []byte) error {
+	var res PayeesResponse
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
+
+// PayeesResponseData payees response data
+//
+// swagger:model PayeesResponseData
+type PayeesResponseData struct {
+
+	// payees
+	// Required: true
+	Payees []*Payee `json:"payees"`
+
+	// The knowledge of the server
+	// Required: true
+	ServerKnowledge *int64 `json:"server_knowledge"`
+}
+
+// Validate validates this payees response data
+func (m *PayeesResponseData) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validatePayees(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateServerKnowledge(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *PayeesResponseData) validatePayees(formats strfmt.Registry) error {
+
+	if err := validate.Required("data"+"."+"payees", "body", m.Payees); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(m.Payees); i++ {
+		if swag.IsZero(m.Payees[i]) { // not required
+			continue
+		}
+
+		if m.Payees[i] != nil {
+			if err := m.Payees[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("data" + "." + "payees" + "." + strconv.Itoa(i))
+				} else if ce, ok := err.(*errors.CompositeError); ok {
+					return ce.ValidateName("data" + "." + "payees" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+func (m *PayeesResponseData) validateServerKnowledge(formats strfmt.Registry) error {
+
+	if err := validate.Required("data"+"."+"server_knowledge", "body", m.ServerKnowledge); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ContextValidate validate this payees response data based on the context it is used
+func (m *PayeesResponseData) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.contextValidatePayees(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *PayeesResponseData) contextValidatePayees(ctx context.Context, formats strfmt.Registry) error {
+
+	for i := 0; i < len(m.Payees); i++ {
+
+		if m.Payees[i] != nil {
+			if err := m.Payees[i].ContextValidate(ctx, formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("data" + "." + "payees" + "." + strconv.Itoa(i))
+				} else if ce, ok := err.(*errors.CompositeError); ok {
+					return ce.ValidateName("data" + "." + "payees" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *PayeesResponseData) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *PayeesResponseData) UnmarshalBinary(b []byte) error {
+	var res PayeesResponseData
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
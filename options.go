@@ -0,0 +1,288 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dbinit/ynab-amazon-import/amazon"
+	"github.com/dbinit/ynab-amazon-import/models"
+	"github.com/dbinit/ynab-amazon-import/orders"
+	"github.com/dbinit/ynab-amazon-import/txn"
+	"github.com/dbinit/ynab-amazon-import/ynabsync"
+	"github.com/go-openapi/strfmt"
+)
+
+// options holds the flag values shared by commands.
+type options struct {
+	config  string
+	profile string
+
+	token        string
+	tokenEnv     string
+	tokenFile    string
+	tokenCommand string
+	tokenKeyring string
+	client       *ynabsync.Client
+
+	budget  string
+	account string
+
+	cacheDir string
+	refresh  bool
+	cache    *ynabsync.Cache
+
+	source      string
+	orders      string
+	items       string
+	invoices    string
+	diagnostics string
+
+	color             string
+	cleared           string
+	approve           bool
+	rules             string
+	payees            string
+	memoTemplate      string
+	splitMemoTemplate string
+	payeeTemplate     string
+	groceries         string
+
+	batchSize  int
+	maxRetries int
+	checkpoint string
+	journal    string
+	db         string
+
+	ynabURL        string
+	to             string
+	actualURL      string
+	actualBudget   string
+	actualAccount  string
+	fireflyURL     string
+	fireflyAccount string
+}
+
+// budgetFlags registers the budget and, optionally, the account flags.
+func (o *options) budgetFlags(fs *flag.FlagSet, account bool) {
+	fs.StringVar(&o.budget, "budget", "", "YNAB budget name or ID, or \"last-used\" or \"default\"")
+	if account {
+		fs.StringVar(&o.account, "account", "", "YNAB account name or ID")
+	}
+}
+
+// inputFlags registers the order export flags and, optionally, the merge
+// diagnostics flag.
+func (o *options) inputFlags(fs *flag.FlagSet, diagnostics bool) {
+	fs.StringVar(&o.source, "source", amazon.Name, "Order history source: "+strings.Join(orders.Sources(), ", "))
+	fs.StringVar(&o.orders, "orders", "", "Orders export file, e.g. the Amazon orders CSV, an .eml file, mbox file or Maildir for amazon-email, an invoice HTML file or folder for amazon-invoice, or the orders and shipments CSV for amazon-business")
+	fs.StringVar(&o.items, "items", "", "Items export file, for sources exporting items separately, e.g. the Amazon items CSV")
+	fs.StringVar(&o.invoices, "invoices", "", "Optional invoice HTML file or folder whose gift card and card payments overlay the orders of the amazon source")
+	if diagnostics {
+		fs.StringVar(&o.diagnostics, "diagnostics", "", "Optional file to write the merge diagnostics report as JSON, or - for stderr")
+	}
+}
+
+// buildFlags registers the transaction building flags.
+func (o *options) buildFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.color, "color", "", "Optional flag color for imported transactions")
+	fs.StringVar(&o.cleared, "cleared", models.SaveTransactionWithOptionalFieldsClearedCleared, "Cleared status for imported transactions")
+	fs.BoolVar(&o.approve, "approve", false, "Approve imported transactions")
+	fs.StringVar(&o.rules, "rules", "", "Optional JSON file of categorization rules")
+	fs.StringVar(&o.payees, "payees", "", "Optional JSON file mapping sellers to existing YNAB payees")
+	fs.StringVar(&o.memoTemplate, "memo_template", "", "Optional Go template for transaction memos")
+	fs.StringVar(&o.splitMemoTemplate, "split_memo_template", "", "Optional Go template for split transaction memos")
+	fs.StringVar(&o.payeeTemplate, "payee_template", "", "Optional Go template for payee names")
+	fs.StringVar(&o.groceries, "groceries", "", "Optional YNAB category name or ID to import each Amazon Fresh and Whole Foods Market order into as a whole, rather than splitting it by item")
+}
+
+// postFlags registers the transaction posting flags.
+func (o *options) postFlags(fs *flag.FlagSet) {
+	fs.IntVar(&o.batchSize, "batch_size", 100, "Number of transactions to post per request")
+	fs.IntVar(&o.maxRetries, "max_retries", 5, "Number of times to retry rate limited or failed requests")
+	fs.StringVar(&o.checkpoint, "checkpoint", "", "Optional file recording posted batches, so a failed run can be resumed")
+}
+
+// journalFlags registers the journal flag.
+func (o *options) journalFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.journal, "journal", defaultJournalDir(), "Directory of the import journal, or empty to disable it")
+}
+
+// require checks that the named flags are set.
+func require(fs *flag.FlagSet, names ...string) error {
+	var missing []string
+	for _, n := range names {
+		if f := fs.Lookup(n); f == nil || f.Value.String() == "" {
+			missing = append(missing, n)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required flag(s): %v", missing)
+	}
+	return nil
+}
+
+// loadOrders loads the exports of the selected source, merging any separately
+// exported orders and items and overlaying any invoices.
+func (o *options) loadOrders() (map[string]*orders.Order, *orders.MergeReport, error) {
+	s, err := orders.Lookup(o.source)
+	if err != nil {
+		return nil, nil, err
+	}
+	if o.invoices != "" && o.source != amazon.Name {
+		return nil, nil, fmt.Errorf("only the %s source reads invoices", amazon.Name)
+	}
+	return s.Load(orders.Files{Orders: o.orders, Items: o.items, Invoices: o.invoices})
+}
+
+// loadMerged loads the order exports, emits the merge diagnostics and records
+// the orders in the order database.
+func (o *options) loadMerged() (map[string]*orders.Order, error) {
+	merged, report, err := o.loadOrders()
+	if err != nil {
+		return nil, err
+	}
+	if err := emitReport(report, o.diagnostics); err != nil {
+		return nil, err
+	}
+	o.recordOrders(merged, report)
+	return merged, nil
+}
+
+// emitReport writes a merge report to the named file, or "-" for stderr, or
+// logs each diagnostic if name is empty.
+func emitReport(r *orders.MergeReport, name string) (err error) {
+	switch name {
+	case "":
+		return r.WriteText(log.Writer())
+	case "-":
+		return r.WriteJSON(os.Stderr)
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("os.Create(%q): %w", name, err)
+	}
+	defer func() {
+		if ferr := f.Close(); ferr != nil && err == nil {
+			err = fmt.Errorf("(os.File).Close(%q): %w", name, ferr)
+		}
+	}()
+	return r.WriteJSON(f)
+}
+
+// newBuilder returns a transaction builder for the account. Without a budget
+// it works offline, so payees aren't mapped to existing YNAB payees and
+// category names can't be resolved.
+func (o *options) newBuilder(ctx context.Context, budgetID, accountID *strfmt.UUID) (*txn.Builder, error) {
+	if err := txn.ValidateFields(o.cleared, &o.color); err != nil {
+		return nil, fmt.Errorf("invalid transaction fields: %w", err)
+	}
+	b := &txn.Builder{AccountID: accountID, Cleared: o.cleared, Approved: o.approve, FlagColor: o.color}
+	var err error
+	if b.Rules, err = txn.LoadRules(o.rules); err != nil {
+		return nil, err
+	}
+	rules := b.Rules
+	if o.groceries != "" {
+		b.Groceries = &txn.Rule{Name: "groceries"}
+		if strfmt.IsUUID(o.groceries) {
+			b.Groceries.CategoryID = strfmt.UUID(o.groceries)
+		} else {
+			b.Groceries.CategoryName = o.groceries
+		}
+		rules = append(rules[:len(rules):len(rules)], b.Groceries)
+	}
+	if txn.NamesCategories(rules) && budgetID == nil {
+		log.Printf("warning: rules naming categories don't apply without a budget")
+	} else if txn.NamesCategories(rules) {
+		gs, err := o.metaCache().Categories(ctx, budgetID)
+		if err != nil {
+			return nil, err
+		}
+		if err := txn.ResolveCategories(rules, gs); err != nil {
+			return nil, err
+		}
+	}
+	if txn.RoutesAccounts(b.Rules) && budgetID == nil {
+		log.Printf("warning: rules routing orders to YNAB accounts don't apply without a budget")
+	} else if txn.RoutesAccounts(b.Rules) {
+		if err := o.resolveAccounts(ctx, b.Rules); err != nil {
+			return nil, err
+		}
+	}
+	if b.Templates, err = txn.ParseTemplates(o.memoTemplate, o.splitMemoTemplate, o.payeeTemplate); err != nil {
+		return nil, err
+	}
+	if b.Payees, err = txn.LoadPayeePolicy(o.payees); err != nil {
+		return nil, err
+	}
+	if b.Payees != nil && budgetID != nil {
+		ps, err := o.metaCache().Payees(ctx, budgetID)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("%d existing payees found", b.Payees.SetPayees(ps))
+	}
+	return b, nil
+}
+
+// resolveAccounts resolves the YNAB accounts of rules routing orders to them
+// in the selected budget.
+func (o *options) resolveAccounts(ctx context.Context, rs []*txn.Rule) error {
+	b, err := o.metaCache().Budget(ctx, o.budget)
+	if err != nil {
+		return err
+	}
+	as, err := o.metaCache().Accounts(ctx, b.ID)
+	if err != nil {
+		return err
+	}
+	return txn.ResolveAccounts(rs, func(account string) (*strfmt.UUID, error) {
+		a, err := ynabsync.FindAccount(b, as, account)
+		if err != nil {
+			return nil, err
+		}
+		return a.ID, nil
+	})
+}
+
+// buildTransactions resolves the budget and account, loads the order exports
+// and builds and checks the transactions to import. If the checks fail, the
+// transactions are returned with a *txn.CheckError.
+func (o *options) buildTransactions(ctx context.Context) (budgetID, accountID *strfmt.UUID, txns []*models.SaveTransaction, err error) {
+	budgetID, accountID, err = budgetAccount(ctx, o.budget, o.account, o.metaCache())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	merged, err := o.loadMerged()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	txns, err = o.buildTxns(ctx, merged, budgetID, accountID)
+	return budgetID, accountID, txns, err
+}
+
+// buildTxns builds and checks the transactions for the account. If the checks
+// fail, the transactions are returned with a *txn.CheckError.
+func (o *options) buildTxns(ctx context.Context, merged map[string]*orders.Order, budgetID, accountID *strfmt.UUID) ([]*models.SaveTransaction, error) {
+	b, err := o.newBuilder(ctx, budgetID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	txns, err := b.Transactions(merged)
+	if err != nil {
+		return nil, err
+	}
+	if len(txns) == 0 {
+		return nil, fmt.Errorf("nothing to import")
+	}
+	// Return the transactions with any check error so they can be previewed.
+	return txns, txn.Check(txns, time.Now())
+}
+
+// ptrOf returns a pointer to a value of any type.
+func ptrOf[T any](v T) *T { return &v }
@@ -0,0 +1,284 @@
+// Package orderdb is a local database of the orders read from order history
+// exports, the merge diagnostics explaining their amounts and the YNAB
+// transactions created for them, so that a charge can be traced back to its
+// order long after the exports are gone. The database is a single JSON file,
+// rewritten whole on each save under a lock file.
+package orderdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dbinit/ynab-amazon-import/orders"
+	"github.com/dbinit/ynab-amazon-import/txn"
+	"github.com/dbinit/ynab-amazon-import/ynabsync"
+)
+
+// DB is an order database. Records are keyed by the import ID of their
+// order shipment, which is also how YNAB transactions are matched to them.
+type DB struct {
+	Records map[string]*Record `json:"records"`
+
+	name string
+}
+
+// Record is an order shipment as last read, and what became of it.
+type Record struct {
+	ImportID string        `json:"import_id"`
+	Source   string        `json:"source"`
+	Order    *orders.Order `json:"order"`
+
+	// Diagnostics are the merge decisions and skipped inputs concerning the
+	// order shipment, such as refunded items and unexplained remainders.
+	Diagnostics []*orders.Diagnostic `json:"diagnostics,omitempty"`
+
+	// Transactions are the YNAB transactions created for the order shipment.
+	Transactions []*Transaction `json:"transactions,omitempty"`
+
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// Transaction is a YNAB transaction created for an order shipment.
+type Transaction struct {
+	BudgetID string    `json:"budget_id"`
+	ID       string    `json:"id"`
+	Created  time.Time `json:"created"`
+}
+
+// Open reads the database in the named file, or starts an empty one if the
+// file doesn't exist yet.
+func Open(name string) (*DB, error) {
+	db := &DB{Records: make(map[string]*Record), name: name}
+	b, err := os.ReadFile(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return db, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile(%q): %w", name, err)
+	}
+	if err := json.Unmarshal(b, db); err != nil {
+		return nil, fmt.Errorf("failed to parse order database %q: %w", name, err)
+	}
+	if db.Records == nil {
+		db.Records = make(map[string]*Record)
+	}
+	return db, nil
+}
+
+// Save writes the database back to its file.
+func (db *DB) Save() error {
+	if err := os.MkdirAll(filepath.Dir(db.name), 0o700); err != nil {
+		return fmt.Errorf("os.MkdirAll(%q): %w", filepath.Dir(db.name), err)
+	}
+	b, err := json.Marshal(db)
+	if err != nil {
+		return fmt.Errorf("json.Marshal(): %w", err)
+	}
+	// Write and rename so concurrent runs never read a partial file.
+	tmp := db.name + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return fmt.Errorf("os.WriteFile(%q): %w", tmp, err)
+	}
+	if err := os.Rename(tmp, db.name); err != nil {
+		return fmt.Errorf("os.Rename(%q): %w", tmp, err)
+	}
+	return nil
+}
+
+// Lock timing: how long Lock waits for another run's lock, how often it
+// checks, and how old a lock must be to be taken as left by a crashed run.
+const (
+	lockTimeout = 30 * time.Second
+	lockPoll    = 100 * time.Millisecond
+	lockStale   = 5 * time.Minute
+)
+
+// Lock takes the lock of the database in the named file, so concurrent runs
+// updating it don't lose each other's changes, and returns the function
+// releasing it. The lock is a file next to the database, created exclusively.
+func Lock(name string) (unlock func() error, err error) {
+	if err := os.MkdirAll(filepath.Dir(name), 0o700); err != nil {
+		return nil, fmt.Errorf("os.MkdirAll(%q): %w", filepath.Dir(name), err)
+	}
+	lock := name + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(lock, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if err == nil {
+			if err := f.Close(); err != nil {
+				return nil, fmt.Errorf("(os.File).Close(%q): %w", lock, err)
+			}
+			return func() error {
+				if err := os.Remove(lock); err != nil {
+					return fmt.Errorf("os.Remove(%q): %w", lock, err)
+				}
+				return nil
+			}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("os.OpenFile(%q): %w", lock, err)
+		}
+		if fi, err := os.Stat(lock); err == nil && time.Since(fi.ModTime()) > lockStale {
+			os.Remove(lock)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("order database %q is locked by another run; remove %q if none is running", name, lock)
+		}
+		time.Sleep(lockPoll)
+	}
+}
+
+// Add records merged orders read from a source, with the diagnostics of their
+// merge report. Orders already in the database are replaced by the newly read
+// ones, keeping their transactions. It returns the number of orders added and
+// updated.
+func (db *DB) Add(source string, odm map[string]*orders.Order, report *orders.MergeReport, now time.Time) (added, updated int) {
+	diags := make(map[string][]*orders.Diagnostic)
+	if report != nil {
+		for _, d := range report.Diagnostics {
+			k := d.OrderID + ":" + d.ShipmentDate
+			diags[k] = append(diags[k], d)
+		}
+	}
+	for _, k := range orders.SortedKeys(odm) {
+		od := odm[k]
+		id := txn.ImportID(od)
+		r := db.Records[id]
+		if r == nil {
+			r = &Record{ImportID: id, FirstSeen: now}
+			db.Records[id] = r
+			added++
+		} else {
+			updated++
+		}
+		r.Source, r.Order, r.LastSeen = source, od, now
+		r.Diagnostics = diags[od.ID+":"+od.ShipmentDate.String()]
+	}
+	return added, updated
+}
+
+// Link records the YNAB transactions created in a budget for the orders with
+// their import IDs, returning the number linked. Transactions without an
+// import ID, or for orders not in the database, are skipped.
+func (db *DB) Link(budgetID string, created []*ynabsync.Snapshot, now time.Time) int {
+	n := 0
+	for _, s := range created {
+		r := db.Records[s.ImportID]
+		if s.ImportID == "" || s.ID == "" || r == nil {
+			continue
+		}
+		if r.hasTransaction(s.ID) {
+			continue
+		}
+		r.Transactions = append(r.Transactions, &Transaction{BudgetID: budgetID, ID: s.ID, Created: now})
+		n++
+	}
+	return n
+}
+
+// hasTransaction reports whether a transaction is already linked.
+func (r *Record) hasTransaction(id string) bool {
+	for _, t := range r.Transactions {
+		if t.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Query selects records. Empty conditions match everything, and records must
+// match all of the conditions given.
+type Query struct {
+	// OrderID matches order IDs exactly, or by prefix.
+	OrderID string
+
+	// Title matches orders with an item whose title contains it, ignoring
+	// case.
+	Title string
+
+	// ASIN matches orders with an item with the ASIN/ISBN, ignoring case.
+	ASIN string
+
+	// Amount matches orders charged this amount, or with an item or payment
+	// of this amount, ignoring the sign.
+	Amount int64
+}
+
+// Find returns the records matching a query, by shipment date and order ID.
+func (db *DB) Find(q Query) []*Record {
+	var rs []*Record
+	for _, r := range db.Records {
+		if q.match(r) {
+			rs = append(rs, r)
+		}
+	}
+	sort.Slice(rs, func(i, j int) bool { return rs[i].Order.Key() < rs[j].Order.Key() })
+	return rs
+}
+
+// match reports whether a record matches the query.
+func (q Query) match(r *Record) bool {
+	od := r.Order
+	if od == nil {
+		return false
+	}
+	if q.OrderID != "" && !strings.HasPrefix(od.ID, q.OrderID) {
+		return false
+	}
+	if q.Title != "" || q.ASIN != "" {
+		found := false
+		for _, it := range od.Items {
+			if q.MatchItem(it) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return q.Amount == 0 || matchAmount(od, abs(q.Amount))
+}
+
+// MatchItem reports whether an item matches the query's item conditions.
+func (q Query) MatchItem(it *orders.Item) bool {
+	if q.Title != "" && !strings.Contains(strings.ToLower(it.Title), strings.ToLower(q.Title)) {
+		return false
+	}
+	return q.ASIN == "" || strings.EqualFold(it.ASIN, q.ASIN)
+}
+
+// matchAmount reports whether an order, one of its items or one of its
+// payments has an amount.
+func matchAmount(od *orders.Order, n int64) bool {
+	if abs(od.TotalCharged) == n {
+		return true
+	}
+	for _, it := range od.Items {
+		if abs(it.Total) == n {
+			return true
+		}
+	}
+	for _, p := range od.Payments {
+		if abs(p.Amount) == n {
+			return true
+		}
+	}
+	return false
+}
+
+// abs returns the absolute value of an amount.
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,350 @@
+package orders
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// Merge diagnostic kinds.
+const (
+	// Items were matched to a shipment with the same order ID but a different
+	// shipment date because their amount matched.
+	DiagAmountMatch = "amount_match"
+	// Items were matched to the nearest shipment with the same order ID.
+	DiagDateMatch = "date_match"
+	// Items were added to a shipment that already had items.
+	DiagCombined = "combined"
+	// Items had no order record at all.
+	DiagMissingOrder = "missing_order"
+	// An order had no item records.
+	DiagMissingItems = "missing_items"
+	// Order tax exceeding item tax was assigned to shipping.
+	DiagShippingTax = "shipping_tax"
+	// A balancing item was added for an unexplained remainder.
+	DiagRemainder = "remainder"
+	// An order was read from a record of the order rather than of a
+	// shipment, so it is dated by the order.
+	DiagUnshipped = "unshipped"
+	// An input record couldn't be parsed and was skipped.
+	DiagUnparsed = "unparsed"
+	// A refunded or substituted item was left out of its order.
+	DiagRefunded = "refunded"
+	// An invoice's card charges didn't add up with its totals, so they were
+	// left out of the shipments' payments.
+	DiagChargeMismatch = "charge_mismatch"
+	// An invoice shipment had no order shipment to overlay its payments on,
+	// or paid more by gift card than the shipment's total.
+	DiagInvoiceUnmatched = "invoice_unmatched"
+	// The remainder of a grocery order was taken as tips and fees, or netted
+	// out of its items.
+	DiagGrocery = "grocery"
+)
+
+// GroceryFeesMemo is the memo of the fee added for the unexplained charges of
+// a grocery order, which are usually its tip and fees.
+const GroceryFeesMemo = "Tips and Fees"
+
+// Diagnostic describes a single decision made while merging orders and
+// items, or an input a source skipped.
+type Diagnostic struct {
+	Kind         string `json:"kind"`
+	OrderID      string `json:"order_id,omitempty"`
+	ShipmentDate string `json:"shipment_date,omitempty"`
+	ItemDate     string `json:"item_date,omitempty"`
+	Amount       int64  `json:"amount,omitempty"`
+	Detail       string `json:"detail"`
+}
+
+func (d *Diagnostic) String() string {
+	s := d.Kind
+	if d.OrderID != "" {
+		s += ": order " + d.OrderID
+	}
+	if d.ShipmentDate != "" {
+		s += " shipped " + d.ShipmentDate
+	}
+	if d.Amount != 0 {
+		s += fmt.Sprintf(", amount %d", d.Amount)
+	}
+	return s + ": " + d.Detail
+}
+
+// MergeReport summarizes how orders and items were merged.
+type MergeReport struct {
+	Orders      int           `json:"orders"`
+	ItemGroups  int           `json:"item_groups"`
+	ExactMatch  int           `json:"exact_match"`
+	Diagnostics []*Diagnostic `json:"diagnostics"`
+}
+
+// add appends a diagnostic to the report.
+func (r *MergeReport) add(kind string, od *Order, itemDate string, amount int64, format string, args ...any) {
+	r.Diagnostics = append(r.Diagnostics, &Diagnostic{
+		Kind:         kind,
+		OrderID:      od.ID,
+		ShipmentDate: od.ShipmentDate.String(),
+		ItemDate:     itemDate,
+		Amount:       amount,
+		Detail:       fmt.Sprintf(format, args...),
+	})
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *MergeReport) WriteJSON(w io.Writer) error {
+	j, err := json.MarshalIndent(r, "", "\t")
+	if err != nil {
+		return fmt.Errorf("json.MarshalIndent(): %w", err)
+	}
+	_, err = fmt.Fprintf(w, "%s\n", j)
+	return err
+}
+
+// WriteText writes a summary line and a line for each diagnostic.
+func (r *MergeReport) WriteText(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "merged %d item groups into %d orders (%d exact, %d diagnostics)\n",
+		r.ItemGroups, r.Orders, r.ExactMatch, len(r.Diagnostics))
+	for _, d := range r.Diagnostics {
+		if err != nil {
+			break
+		}
+		_, err = fmt.Fprintln(w, d)
+	}
+	return err
+}
+
+// shipment tracks the item groups assigned to an order record while merging.
+type shipment struct {
+	key    string
+	od     *Order
+	groups []*Order
+}
+
+// itemsTotal returns the total of the assigned item groups.
+func (s *shipment) itemsTotal() int64 {
+	var n int64
+	for _, g := range s.groups {
+		n += g.TotalCharged
+	}
+	return n
+}
+
+// remainder returns the order amount not yet explained by assigned items.
+func (s *shipment) remainder() int64 {
+	return s.od.itemsExpected() - s.itemsTotal()
+}
+
+// Merge merges parsed orders and parsed items, both keyed by Order.Key, into
+// odm. Item groups are matched to order records by shipment date and order ID,
+// then by order ID and amount, then by order ID and nearest shipment date.
+// Refunded items are left out, and order tax beyond the item tax is assigned
+// to shipping. A grocery order charged more than its items gets a fee for the
+// difference, and one charged less has the difference netted out of its items
+// by their totals. Otherwise an item with the MissingSeller balances any
+// remainder. Every adjustment is recorded in the returned report.
+func Merge(odm, idm map[string]*Order) (map[string]*Order, *MergeReport) {
+	report := &MergeReport{Orders: len(odm), ItemGroups: len(idm)}
+
+	// Index order records by order ID.
+	byKey := make(map[string]*shipment, len(odm))
+	byOrder := make(map[string][]*shipment)
+	for _, key := range SortedKeys(odm) {
+		s := &shipment{key: key, od: odm[key]}
+		byKey[key] = s
+		byOrder[s.od.ID] = append(byOrder[s.od.ID], s)
+	}
+
+	// Match item groups by shipment date and order ID.
+	var pending []string
+	for _, key := range SortedKeys(idm) {
+		if s, ok := byKey[key]; ok {
+			s.groups = append(s.groups, idm[key])
+			report.ExactMatch++
+			continue
+		}
+		pending = append(pending, key)
+	}
+
+	// Match the remaining item groups by order ID.
+	for _, key := range pending {
+		id := idm[key]
+		itemDate := id.ShipmentDate.String()
+		candidates := byOrder[id.ID]
+		if len(candidates) == 0 {
+			// No matching order, so just copy the item pseudo-order.
+			odm[key] = id
+			report.add(DiagMissingOrder, id, itemDate, id.TotalCharged,
+				"no order record for items shipped %s; importing items without order charges", itemDate)
+			continue
+		}
+
+		// Prefer a shipment without items whose amount matches, then any
+		// shipment whose unexplained remainder matches.
+		var s *shipment
+		for _, c := range candidates {
+			if len(c.groups) == 0 && c.remainder() == id.TotalCharged {
+				s = c
+				report.add(DiagAmountMatch, c.od, itemDate, id.TotalCharged,
+					"items shipped %s match order total for shipment %s", itemDate, c.od.ShipmentDate)
+				break
+			}
+		}
+		if s == nil {
+			for _, c := range candidates {
+				if len(c.groups) > 0 && c.remainder() == id.TotalCharged {
+					s = c
+					report.add(DiagAmountMatch, c.od, itemDate, id.TotalCharged,
+						"items shipped %s match remaining balance of shipment %s", itemDate, c.od.ShipmentDate)
+					break
+				}
+			}
+		}
+		if s == nil {
+			// Fall back to the nearest shipment, preferring ones without items
+			// and then ones with an unexplained remainder.
+			s = nearestShipment(candidates, id, func(c *shipment) bool { return len(c.groups) == 0 })
+			if s == nil {
+				s = nearestShipment(candidates, id, func(c *shipment) bool { return c.remainder() != 0 })
+			}
+			if s == nil {
+				s = nearestShipment(candidates, id, func(*shipment) bool { return true })
+			}
+			report.add(DiagDateMatch, s.od, itemDate, id.TotalCharged,
+				"items shipped %s assigned to nearest shipment %s by order ID", itemDate, s.od.ShipmentDate)
+		}
+		if len(s.groups) > 0 {
+			report.add(DiagCombined, s.od, itemDate, id.TotalCharged,
+				"items shipped %s combined with %d other item group(s)", itemDate, len(s.groups))
+		}
+		s.groups = append(s.groups, id)
+	}
+
+	// Merge items into each order and balance the amounts.
+	for _, key := range SortedKeys(byKey) {
+		s := byKey[key]
+		od := s.od
+		if len(s.groups) == 0 {
+			report.add(DiagMissingItems, od, "", od.TotalCharged,
+				"no item records for this shipment")
+			continue
+		}
+
+		// Copy over items, leaving out refunded ones.
+		var itemsTax int64
+		for _, g := range s.groups {
+			od.Grocery = od.Grocery || g.Grocery
+			od.SubscribeAndSave = od.SubscribeAndSave || g.SubscribeAndSave
+			for _, it := range g.Items {
+				if it.Refunded {
+					report.add(DiagRefunded, od, g.ShipmentDate.String(), it.Total,
+						"%q was refunded or substituted, so it isn't part of the charge", it.Title)
+					continue
+				}
+				od.Items = append(od.Items, it)
+			}
+			itemsTax += g.TaxCharged
+		}
+
+		// If there is more total tax than item tax, assume it is for shipping.
+		if st := od.TaxCharged - itemsTax; st < 0 && od.ShippingCharge < 0 {
+			od.ShippingCharge += st
+			report.add(DiagShippingTax, od, "", st,
+				"order tax %d exceeds item tax %d; difference added to shipping", od.TaxCharged, itemsTax)
+		}
+
+		// Adjust grocery orders for any remaining balance.
+		itemsTotal := s.itemsTotal()
+		r := od.itemsExpected() - itemsTotal
+		if r != 0 && od.Grocery {
+			r = adjustGrocery(od, r, report)
+		}
+
+		// Add an item for any remaining balance.
+		if r != 0 {
+			od.Items = append(od.Items, &Item{
+				Title:  od.URL,
+				Seller: MissingSeller,
+				Total:  r,
+			})
+			report.add(DiagRemainder, od, "", r,
+				"total %d - shipping %d - promotions %d - fees %d - items %d leaves %d unexplained",
+				od.TotalCharged, od.ShippingCharge, od.TotalPromotions, od.FeesTotal(), itemsTotal, r)
+		}
+	}
+
+	return odm, report
+}
+
+// adjustGrocery explains the remainder of a grocery order, returning what is
+// left unexplained. A charge beyond the items is taken as tips and fees the
+// source didn't list, and a shortfall as weighed items coming in lighter,
+// substitutions and refunds, which is netted out of the items by their
+// totals, rounded to the cent. No item is netted past zero, so a shortfall
+// bigger than the items is left unexplained, and items netted to zero are
+// left out of the order as refunded.
+func adjustGrocery(od *Order, r int64, report *MergeReport) int64 {
+	if r < 0 {
+		od.Fees = append(od.Fees, &Fee{Memo: GroceryFeesMemo, Amount: r})
+		report.add(DiagGrocery, od, "", r,
+			"grocery order charged %d more than its items; added as tips and fees", -r)
+		return 0
+	}
+	var total int64
+	for _, it := range od.Items {
+		total += it.Total
+	}
+	if total == 0 {
+		return r
+	}
+	remaining := r
+	items := od.Items[:0]
+	for i, it := range od.Items {
+		n := remaining
+		if i < len(od.Items)-1 {
+			n = int64(math.Round(float64(r)*float64(it.Total)/float64(total)/10)) * 10
+		}
+		if n > -it.Total {
+			n = -it.Total
+		}
+		if n > remaining {
+			n = remaining
+		}
+		if n < 0 {
+			n = 0
+		}
+		remaining -= n
+		if it.Total != 0 && it.Total+n == 0 {
+			report.add(DiagRefunded, od, "", it.Total,
+				"%q was netted out of the grocery order entirely, so it isn't part of the charge", it.Title)
+			continue
+		}
+		it.Total += n
+		items = append(items, it)
+	}
+	report.add(DiagGrocery, od, "", r-remaining,
+		"grocery order charged %d less than its items, for weighed items, substitutions and refunds; netted out of %d items", r-remaining, len(od.Items))
+	od.Items = items
+	return remaining
+}
+
+// nearestShipment returns the shipment accepted by ok whose shipment date is
+// closest to the item group's shipment date, or nil.
+func nearestShipment(candidates []*shipment, id *Order, ok func(*shipment) bool) *shipment {
+	var best *shipment
+	var bestDiff time.Duration
+	for _, c := range candidates {
+		if !ok(c) {
+			continue
+		}
+		diff := time.Time(c.od.ShipmentDate).Sub(time.Time(id.ShipmentDate))
+		if diff < 0 {
+			diff = -diff
+		}
+		if best == nil || diff < bestDiff {
+			best, bestDiff = c, diff
+		}
+	}
+	return best
+}
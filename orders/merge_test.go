@@ -0,0 +1,238 @@
+package orders
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+)
+
+// testDate returns a shipment date in January 2023.
+func testDate(day int) strfmt.Date {
+	return strfmt.Date(time.Date(2023, 1, day, 0, 0, 0, 0, time.UTC))
+}
+
+// orderMap keys orders by Order.Key.
+func orderMap(ods ...*Order) map[string]*Order {
+	m := make(map[string]*Order, len(ods))
+	for _, od := range ods {
+		m[od.Key()] = od
+	}
+	return m
+}
+
+// itemGroup returns an item group of the order shipped on the day, totalling
+// its items.
+func itemGroup(id string, day int, items ...*Item) *Order {
+	g := &Order{ID: id, ShipmentDate: testDate(day)}
+	for _, it := range items {
+		g.Items = append(g.Items, it)
+		if !it.Refunded {
+			g.TotalCharged += it.Total
+		}
+	}
+	return g
+}
+
+// describe summarizes merged orders and the kinds of the diagnostics.
+func describe(odm map[string]*Order, report *MergeReport) (string, string) {
+	var ods []string
+	for _, k := range SortedKeys(odm) {
+		od := odm[k]
+		s := fmt.Sprintf("%s %s ship %d", od.ShipmentDate, od.ID, od.ShippingCharge)
+		for _, f := range od.Fees {
+			s += fmt.Sprintf(" | %s %d", f.Memo, f.Amount)
+		}
+		for _, it := range od.Items {
+			s += fmt.Sprintf(" | %s %d", it.Title, it.Total)
+		}
+		ods = append(ods, s)
+	}
+	var kinds []string
+	for _, d := range report.Diagnostics {
+		kinds = append(kinds, d.Kind)
+	}
+	return strings.Join(ods, "\n"), strings.Join(kinds, " ")
+}
+
+func TestMergeGrocery(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		od     *Order
+		items  []*Item
+		want   string
+		wantDg string
+	}{
+		{
+			name:   "tip",
+			od:     &Order{TotalCharged: -11500},
+			items:  []*Item{{Title: "Bananas", Total: -1500}, {Title: "Coffee", Total: -8000}},
+			want:   "2023-01-03 111-0000000-0000001 ship 0 | Tips and Fees -2000 | Bananas -1500 | Coffee -8000",
+			wantDg: "grocery",
+		},
+		{
+			name:   "lighter",
+			od:     &Order{TotalCharged: -9000},
+			items:  []*Item{{Title: "Bananas", Total: -2000}, {Title: "Coffee", Total: -8000}},
+			want:   "2023-01-03 111-0000000-0000001 ship 0 | Bananas -1800 | Coffee -7200",
+			wantDg: "grocery",
+		},
+		{
+			// The cheap item's share rounds up to all of it, so it is left out
+			// rather than posted at $0.
+			name:   "rounded to zero",
+			od:     &Order{TotalCharged: -40},
+			items:  []*Item{{Title: "Bag", Total: -10}, {Title: "Bananas", Total: -90}},
+			want:   "2023-01-03 111-0000000-0000001 ship 0 | Bananas -40",
+			wantDg: "refunded grocery",
+		},
+		{
+			// The shortfall is bigger than every item but one.
+			name: "shortfall",
+			od:   &Order{ShippingCharge: -500, TotalCharged: -1500},
+			items: []*Item{
+				{Title: "Bag", Total: -10}, {Title: "Bananas", Total: -1000}, {Title: "Avocados", Total: -4000},
+			},
+			want:   "2023-01-03 111-0000000-0000001 ship -500 | Bananas -200 | Avocados -800",
+			wantDg: "refunded grocery",
+		},
+		{
+			// Nothing but shipping was charged, so every item is netted out.
+			name:   "all refunded",
+			od:     &Order{ShippingCharge: -500, TotalCharged: -500},
+			items:  []*Item{{Title: "Bananas", Total: -1000}, {Title: "Avocados", Total: -4000}},
+			want:   "2023-01-03 111-0000000-0000001 ship -500",
+			wantDg: "refunded refunded grocery",
+		},
+		{
+			// A shortfall bigger than the items is left unexplained.
+			name:   "bigger than items",
+			od:     &Order{TotalCharged: 1000},
+			items:  []*Item{{Title: "Bananas", Total: -1000}},
+			want:   "2023-01-03 111-0000000-0000001 ship 0 | 111-0000000-0000001 1000",
+			wantDg: "refunded grocery remainder",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			od := tc.od
+			od.ID, od.ShipmentDate, od.URL = "111-0000000-0000001", testDate(3), "111-0000000-0000001"
+			g := itemGroup(od.ID, 3, tc.items...)
+			g.Grocery = true
+			odm, report := Merge(orderMap(od), orderMap(g))
+			got, dg := describe(odm, report)
+			if got != tc.want {
+				t.Errorf("Merge() =\n\t%s\nwant\n\t%s", got, tc.want)
+			}
+			if dg != tc.wantDg {
+				t.Errorf("Merge() diagnostics %q, want %q", dg, tc.wantDg)
+			}
+			for _, od := range odm {
+				for _, it := range od.Items {
+					if it.Total == 0 {
+						t.Errorf("Merge() kept $0 item %q", it.Title)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	const id = "111-0000000-0000001"
+	order := func(day int, shipping, tax, total int64) *Order {
+		return &Order{ID: id, URL: id, ShipmentDate: testDate(day), ShippingCharge: shipping, TaxCharged: tax, TotalCharged: total}
+	}
+	taxed := func(g *Order, tax int64) *Order {
+		g.TaxCharged = tax
+		return g
+	}
+	for _, tc := range []struct {
+		name   string
+		orders []*Order
+		groups []*Order
+		want   string
+		wantDg string
+	}{
+		{
+			name:   "exact",
+			orders: []*Order{order(3, 0, 0, -1000)},
+			groups: []*Order{itemGroup(id, 3, &Item{Title: "A", Total: -1000})},
+			want:   "2023-01-03 111-0000000-0000001 ship 0 | A -1000",
+		},
+		{
+			// Both shipments are a day from the items, but only one matches
+			// their amount.
+			name:   "amount match",
+			orders: []*Order{order(3, 0, 0, -1000), order(5, 0, 0, -2500)},
+			groups: []*Order{itemGroup(id, 4, &Item{Title: "B", Total: -2500})},
+			want: "2023-01-03 111-0000000-0000001 ship 0\n" +
+				"2023-01-05 111-0000000-0000001 ship 0 | B -2500",
+			wantDg: "amount_match missing_items",
+		},
+		{
+			name:   "remaining balance",
+			orders: []*Order{order(3, 0, 0, -3000)},
+			groups: []*Order{
+				itemGroup(id, 3, &Item{Title: "A", Total: -1000}),
+				itemGroup(id, 4, &Item{Title: "B", Total: -2000}),
+			},
+			want:   "2023-01-03 111-0000000-0000001 ship 0 | A -1000 | B -2000",
+			wantDg: "amount_match combined",
+		},
+		{
+			// No amount matches, so the items go to the nearest shipment
+			// without items, and the rest of its total is a remainder.
+			name:   "nearest shipment",
+			orders: []*Order{order(3, 0, 0, -1000), order(10, 0, 0, -2500)},
+			groups: []*Order{itemGroup(id, 9, &Item{Title: "C", Total: -700})},
+			want: "2023-01-03 111-0000000-0000001 ship 0\n" +
+				"2023-01-10 111-0000000-0000001 ship 0 | C -700 | 111-0000000-0000001 -1800",
+			wantDg: "date_match missing_items remainder",
+		},
+		{
+			name:   "nearest with items",
+			orders: []*Order{order(3, 0, 0, -3000), order(10, 0, 0, -2500)},
+			groups: []*Order{
+				itemGroup(id, 3, &Item{Title: "A", Total: -1000}),
+				itemGroup(id, 10, &Item{Title: "B", Total: -2500}),
+				itemGroup(id, 8, &Item{Title: "C", Total: -700}),
+			},
+			want: "2023-01-03 111-0000000-0000001 ship 0 | A -1000 | C -700 | 111-0000000-0000001 -1300\n" +
+				"2023-01-10 111-0000000-0000001 ship 0 | B -2500",
+			wantDg: "date_match combined remainder",
+		},
+		{
+			name:   "shipping tax",
+			orders: []*Order{order(3, -500, -300, -1800)},
+			groups: []*Order{taxed(itemGroup(id, 3, &Item{Title: "A", Total: -1100}), -100)},
+			want:   "2023-01-03 111-0000000-0000001 ship -700 | A -1100",
+			wantDg: "shipping_tax",
+		},
+		{
+			name:   "refunded",
+			orders: []*Order{order(3, 0, 0, -1000)},
+			groups: []*Order{itemGroup(id, 3, &Item{Title: "A", Total: -1000}, &Item{Title: "B", Total: -400, Refunded: true})},
+			want:   "2023-01-03 111-0000000-0000001 ship 0 | A -1000",
+			wantDg: "refunded",
+		},
+		{
+			name:   "missing order",
+			groups: []*Order{itemGroup("111-0000000-0000002", 4, &Item{Title: "D", Total: -500})},
+			want:   "2023-01-04 111-0000000-0000002 ship 0 | D -500",
+			wantDg: "missing_order",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			odm, report := Merge(orderMap(tc.orders...), orderMap(tc.groups...))
+			got, dg := describe(odm, report)
+			if got != tc.want {
+				t.Errorf("Merge() =\n\t%s\nwant\n\t%s", got, tc.want)
+			}
+			if dg != tc.wantDg {
+				t.Errorf("Merge() diagnostics %q, want %q", dg, tc.wantDg)
+			}
+		})
+	}
+}
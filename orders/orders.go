@@ -0,0 +1,214 @@
+// Package orders is the order model shared by the order sources and the
+// transaction builders: orders grouped by shipment, their items, the merging
+// of separately exported orders and items, and the registry of sources that
+// read retailers' order history exports.
+//
+// Amounts are YNAB milliunits, with charges negative, e.g. -12340 for a
+// $12.34 charge.
+package orders
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/go-openapi/strfmt"
+)
+
+// MissingSeller is the seller of the item balancing an order whose items don't
+// add up to its total.
+const MissingSeller = "Missing"
+
+// Order is a shipment of an order. An order shipped in several shipments has
+// an Order for each shipment date.
+type Order struct {
+	ID           string
+	ShipmentDate strfmt.Date
+
+	// Retailer holds the defaults of the source the order was read from.
+	Retailer Retailer
+
+	// URL links to the order details, if the retailer has such a page.
+	URL string
+
+	ShippingCharge  int64
+	TotalPromotions int64
+	TaxCharged      int64
+	TotalCharged    int64
+
+	// Payments breaks TotalCharged down by payment instrument, if the source
+	// knows how the order was paid.
+	Payments []*Payment
+
+	// Grocery is set for grocery delivery orders, such as Amazon Fresh and
+	// Whole Foods Market orders, whose charges are adjusted for weighed
+	// items, substitutions and refunds after the items are listed.
+	Grocery bool
+
+	// SubscribeAndSave is set for Subscribe & Save deliveries, if the source
+	// can tell. Amazon's order history CSVs can't.
+	SubscribeAndSave bool
+
+	// Fees are the order's tips and fees, which are part of TotalCharged.
+	Fees []*Fee
+
+	// PONumber, AccountGroup, Buyer and Approver are the purchasing details
+	// of business orders, if the source has them.
+	PONumber     string
+	AccountGroup string
+	Buyer        string
+	Approver     string
+
+	Items []*Item
+}
+
+// Payment is the part of an order's charge paid with a payment instrument.
+type Payment struct {
+	// Instrument describes the instrument, e.g. "Visa ending in 1234".
+	Instrument string
+	GiftCard   bool
+	Amount     int64
+}
+
+// Fee is a charge of an order for something other than its items and
+// shipping, such as a delivery tip or a bag fee.
+type Fee struct {
+	Memo   string
+	Amount int64
+}
+
+func (o *Order) String() string {
+	var items string
+	for i, it := range o.Items {
+		items += fmt.Sprintf("\n\t%02d %s", i, it)
+	}
+	return fmt.Sprintf(
+		"Date: %s, Ship: %d, Promo: %d, Tax: %d, Total: %d%s",
+		o.ShipmentDate, o.ShippingCharge, o.TotalPromotions, o.TaxCharged, o.TotalCharged, items)
+}
+
+// Key returns the key of the order in an order map, which sorts by shipment
+// date.
+func (o *Order) Key() string {
+	return o.ShipmentDate.String() + "-" + o.ID
+}
+
+// HasMissing reports whether the order has an item balancing an unexplained
+// remainder.
+func (o *Order) HasMissing() bool {
+	for _, it := range o.Items {
+		if it.Seller == MissingSeller {
+			return true
+		}
+	}
+	return false
+}
+
+// FeesTotal returns the total of the order's tips and fees.
+func (o *Order) FeesTotal() int64 {
+	var n int64
+	for _, f := range o.Fees {
+		n += f.Amount
+	}
+	return n
+}
+
+// GiftCardTotal returns the part of TotalCharged paid by gift card.
+func (o *Order) GiftCardTotal() int64 {
+	var n int64
+	for _, p := range o.Payments {
+		if p.GiftCard {
+			n += p.Amount
+		}
+	}
+	return n
+}
+
+// CardCharged returns the part of TotalCharged paid other than by gift card,
+// which is what the paying account is charged.
+func (o *Order) CardCharged() int64 {
+	return o.TotalCharged - o.GiftCardTotal()
+}
+
+// itemsExpected returns the item total implied by the order amounts.
+func (o *Order) itemsExpected() int64 {
+	return o.TotalCharged - o.ShippingCharge - o.TotalPromotions - o.FeesTotal()
+}
+
+// Item is an item of an order shipment.
+type Item struct {
+	Title     string
+	Seller    string
+	Quantity  int64
+	UnitPrice int64
+	ASIN      string
+	Category  string
+	UNSPSC    string
+
+	SubtotalTax int64
+	Total       int64
+
+	// Refunded is set for items that were refunded, or substituted by
+	// another item, before the order was charged. Sources leave them out of
+	// the totals of their item groups, and Merge leaves them out of orders.
+	Refunded bool
+}
+
+func (it *Item) String() string {
+	return fmt.Sprintf(
+		"Seller: %q, Qty: %d, Price: %d, ASIN: %q, Tax: %d, Total: %d, Title: %q",
+		it.Seller, it.Quantity, it.UnitPrice, it.ASIN, it.SubtotalTax, it.Total, it.Title)
+}
+
+// GetOrAdd returns the order in m with the ID and shipment date, adding it if
+// there isn't one.
+func GetOrAdd(m map[string]*Order, id string, date strfmt.Date) *Order {
+	o := &Order{ID: id, ShipmentDate: date}
+	if existing, ok := m[o.Key()]; ok {
+		return existing
+	}
+	m[o.Key()] = o
+	return o
+}
+
+// SortedKeys returns the keys of a map in sorted order.
+func SortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Matches a currency string, e.g. "$12.34".
+var moneyRE = regexp.MustCompile(`^([-])?[^\d]*(\d+)(?:[.](\d+))?$`)
+
+// ParseMoney returns the milliunits of a currency string, negated if invert is
+// set. E.g. "$12.34" becomes 12340.
+func ParseMoney(amount string, invert bool) (int64, error) {
+	m := moneyRE.FindStringSubmatch(amount)
+	if m == nil || len(m) != 4 {
+		return 0, fmt.Errorf("failed to parse %q as money", amount)
+	}
+	// Make sure decimal has 3 digits.
+	a, err := strconv.ParseInt(m[1]+m[2]+(m[3] + "000")[:3], 10, 0)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %q as money: %w", amount, err)
+	}
+	if invert {
+		return a * -1, nil
+	}
+	return a, nil
+}
+
+// FormatMoney returns a currency string for milliunits. E.g. 12340 becomes
+// "$12.34".
+func FormatMoney(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	return fmt.Sprintf("%s$%d.%02d", sign, amount/1000, amount%1000/10)
+}
@@ -0,0 +1,89 @@
+package orders
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Retailer holds the defaults of a retailer's orders.
+type Retailer struct {
+	// Payee is the payee of orders and items with no seller, e.g. "Amazon".
+	Payee string
+
+	// ImportPrefix prefixes the import IDs of the orders, e.g. "AMZ:". Each
+	// retailer needs its own, so orders of different retailers with the same
+	// ID don't collide.
+	ImportPrefix string
+
+	// OrderURL is the prefix of the order details page of an order ID, or
+	// empty if the retailer has no such page.
+	OrderURL string
+}
+
+// Files names the exported files of a source. Sources exporting items with
+// their orders only use Orders. Invoices names saved invoice pages whose
+// payments overlay the orders of sources that support it.
+type Files struct {
+	Orders   string
+	Items    string
+	Invoices string
+}
+
+// Source reads a retailer's order history export. Sources register
+// themselves by name with Register, usually in an init function.
+type Source interface {
+	// Retailer returns the defaults of the source's orders, which are also
+	// set on each order loaded.
+	Retailer() Retailer
+
+	// Load reads the exported files into orders keyed by Order.Key. Sources
+	// exporting orders and items separately merge them, and the report
+	// describes how; others return a report with no diagnostics.
+	Load(files Files) (map[string]*Order, *MergeReport, error)
+}
+
+var (
+	sourcesMu sync.RWMutex
+	sources   = make(map[string]Source)
+)
+
+// Register makes a source available by name. It panics if the name is
+// already registered.
+func Register(name string, s Source) {
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+	if _, ok := sources[name]; ok {
+		panic("orders: Register called twice for source " + name)
+	}
+	sources[name] = s
+}
+
+// Lookup returns the source registered with a name.
+func Lookup(name string) (Source, error) {
+	sourcesMu.RLock()
+	defer sourcesMu.RUnlock()
+	s, ok := sources[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown source %q, expected one of %s", name, strings.Join(sourceNames(), ", "))
+	}
+	return s, nil
+}
+
+// Sources returns the names of the registered sources in sorted order.
+func Sources() []string {
+	sourcesMu.RLock()
+	defer sourcesMu.RUnlock()
+	return sourceNames()
+}
+
+// sourceNames returns the sorted source names. The caller holds sourcesMu.
+func sourceNames() []string {
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,168 @@
+// Package reconcile compares, per month, the charges built from a retailer's
+// orders with the transactions of a YNAB account, and lists the unmatched
+// ones on either side.
+package reconcile
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dbinit/ynab-amazon-import/export"
+	"github.com/dbinit/ynab-amazon-import/models"
+	"github.com/dbinit/ynab-amazon-import/orders"
+	"github.com/dbinit/ynab-amazon-import/ynabsync"
+	"github.com/go-openapi/strfmt"
+)
+
+// Month totals a month of charges and YNAB transactions.
+type Month struct {
+	// Month is the month, e.g. "2023-01".
+	Month string
+
+	// Charged totals the month's charges, and YNAB the transactions matching
+	// them and the unmatched transactions of the month.
+	Charged, YNAB                   int64
+	UnmatchedCharges, UnmatchedYNAB int
+}
+
+// Report is the result of reconciling charges with an account.
+type Report struct {
+	Months []*Month
+
+	// Unmatched are the charges with no YNAB transaction, and Extra the
+	// retailer's YNAB transactions with no charge.
+	Unmatched []*models.SaveTransaction
+	Extra     []*models.TransactionDetail
+}
+
+// Since returns the date to fetch the account's transactions from to
+// reconcile charges: the first charge, less the matching days.
+func Since(charges []*models.SaveTransaction, days int) strfmt.Date {
+	from := time.Time(*charges[0].Date)
+	for _, t := range charges {
+		if d := time.Time(*t.Date); d.Before(from) {
+			from = d
+		}
+	}
+	return strfmt.Date(from.AddDate(0, 0, -days))
+}
+
+// New reconciles charges, which must all be for one account, with the
+// account's transactions since Since. Only the transactions carrying the
+// retailer's import IDs or payee are compared, and a charge matches a
+// transaction as for ynabsync.Match. A matched transaction counts in its
+// charge's month, even if it posted in the month before or after, and
+// unmatched transactions only dated within the matching days of the charges'
+// range are left out, as their charges would be outside it.
+func New(charges []*models.SaveTransaction, all []*models.TransactionDetail, r orders.Retailer, days int) *Report {
+	// Only YNAB transactions dated within the charges' range, give or take
+	// the matching days, can be compared.
+	from, to := time.Time(*charges[0].Date), time.Time(*charges[0].Date)
+	for _, t := range charges {
+		if d := time.Time(*t.Date); d.Before(from) {
+			from = d
+		} else if d.After(to) {
+			to = d
+		}
+	}
+	var existing []*models.TransactionDetail
+	first, last := from.AddDate(0, 0, -days), to.AddDate(0, 0, days)
+	for _, e := range all {
+		if e == nil || e.ID == nil || e.Amount == nil || e.Date == nil || (e.Deleted != nil && *e.Deleted) {
+			continue
+		}
+		if d := time.Time(*e.Date); d.Before(first) || d.After(last) || !isRetailerTransaction(e, r) {
+			continue
+		}
+		existing = append(existing, e)
+	}
+
+	months := make(map[string]*Month)
+	month := func(d *strfmt.Date) *Month {
+		k := time.Time(*d).Format("2006-01")
+		if months[k] == nil {
+			months[k] = &Month{Month: k}
+		}
+		return months[k]
+	}
+	rep := &Report{}
+	used := make(map[string]bool)
+	for _, t := range charges {
+		m := month(t.Date)
+		m.Charged += *t.Amount
+		if e := ynabsync.Match(t, existing, used, days); e != nil {
+			used[*e.ID] = true
+			m.YNAB += *e.Amount
+			continue
+		}
+		m.UnmatchedCharges++
+		rep.Unmatched = append(rep.Unmatched, t)
+	}
+	for _, e := range existing {
+		if used[*e.ID] {
+			continue
+		}
+		if d := time.Time(*e.Date); d.Before(from) || d.After(to) {
+			continue
+		}
+		m := month(e.Date)
+		m.YNAB += *e.Amount
+		m.UnmatchedYNAB++
+		rep.Extra = append(rep.Extra, e)
+	}
+	for _, k := range orders.SortedKeys(months) {
+		rep.Months = append(rep.Months, months[k])
+	}
+	return rep
+}
+
+// WriteText writes a table of the months, followed by tables of the unmatched
+// charges and YNAB transactions, if there are any.
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "MONTH\tAMAZON\tYNAB\tDIFFERENCE\tUNMATCHED AMAZON\tUNMATCHED YNAB")
+	for _, m := range r.Months {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%d\n", m.Month, orders.FormatMoney(m.Charged), orders.FormatMoney(m.YNAB), orders.FormatMoney(m.Charged-m.YNAB), m.UnmatchedCharges, m.UnmatchedYNAB)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if len(r.Unmatched) > 0 {
+		fmt.Fprintln(w, "\nAmazon charges not in YNAB:")
+		tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "DATE\tAMOUNT\tIMPORT ID\tPAYEE\tMEMO")
+		for _, t := range r.Unmatched {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", t.Date, orders.FormatMoney(*t.Amount), t.ImportID, export.PayeeName(t), oneLine(t.Memo))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	if len(r.Extra) > 0 {
+		fmt.Fprintln(w, "\nYNAB transactions not in the Amazon orders:")
+		tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "DATE\tAMOUNT\tIMPORT ID\tPAYEE\tMEMO")
+		for _, e := range r.Extra {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", e.Date, orders.FormatMoney(*e.Amount), e.ImportID, e.PayeeName, oneLine(e.Memo))
+		}
+		return tw.Flush()
+	}
+	return nil
+}
+
+// isRetailerTransaction reports whether a YNAB transaction was imported from
+// the retailer's orders, or is paid to its payee.
+func isRetailerTransaction(e *models.TransactionDetail, r orders.Retailer) bool {
+	if r.ImportPrefix != "" && strings.HasPrefix(e.ImportID, r.ImportPrefix) {
+		return true
+	}
+	return r.Payee != "" && strings.Contains(strings.ToLower(e.PayeeName), strings.ToLower(r.Payee))
+}
+
+// oneLine replaces line breaks, which would break a table row.
+func oneLine(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
@@ -0,0 +1,114 @@
+package reconcile
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dbinit/ynab-amazon-import/models"
+	"github.com/dbinit/ynab-amazon-import/orders"
+	"github.com/go-openapi/strfmt"
+)
+
+var retailer = orders.Retailer{Payee: "Amazon", ImportPrefix: "AMZ:"}
+
+// date returns a date in 2023.
+func date(month time.Month, day int) *strfmt.Date {
+	d := strfmt.Date(time.Date(2023, month, day, 0, 0, 0, 0, time.UTC))
+	return &d
+}
+
+// charge returns a charge with an import ID.
+func charge(importID string, d *strfmt.Date, amount int64) *models.SaveTransaction {
+	t := &models.SaveTransaction{Date: d, Amount: &amount}
+	t.ImportID = importID
+	return t
+}
+
+// existing returns a YNAB transaction with an ID made of its fields.
+func existing(importID, payee string, d *strfmt.Date, amount int64) *models.TransactionDetail {
+	e := &models.TransactionDetail{}
+	e.ID = ptrTo(fmt.Sprintf("%s %s %d", importID+payee, d, amount))
+	e.ImportID, e.PayeeName, e.Date, e.Amount = importID, payee, d, &amount
+	return e
+}
+
+// ptrTo returns a pointer to a value of any type.
+func ptrTo[T any](v T) *T { return &v }
+
+func TestNew(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		charges []*models.SaveTransaction
+		all     []*models.TransactionDetail
+		// months are "month charged ynab unmatched-charges unmatched-ynab".
+		months           []string
+		unmatched, extra int
+	}{
+		{
+			// A charge posted before a shipment at the start of the month
+			// counts in the shipment's month.
+			name:    "month start",
+			charges: []*models.SaveTransaction{charge("AMZ:A:2023-02-01", date(2, 1), -1000)},
+			all:     []*models.TransactionDetail{existing("", "Amazon.com", date(1, 30), -1000)},
+			months:  []string{"2023-02 -1000 -1000 0 0"},
+		},
+		{
+			// Unmatched transactions only within the matching days of the
+			// charges are left out, as are older ones.
+			name: "margins",
+			charges: []*models.SaveTransaction{
+				charge("AMZ:A:2023-02-01", date(2, 1), -1000),
+				charge("AMZ:C:2023-02-20", date(2, 20), -3000),
+			},
+			all: []*models.TransactionDetail{
+				existing("", "Amazon", date(1, 29), -500),
+				existing("", "Amazon", date(2, 22), -400),
+				existing("", "Amazon", date(1, 10), -1000),
+			},
+			months:    []string{"2023-02 -4000 0 2 0"},
+			unmatched: 2,
+		},
+		{
+			name:    "extra",
+			charges: []*models.SaveTransaction{charge("AMZ:B:2023-02-10", date(2, 10), -2000)},
+			all: []*models.TransactionDetail{
+				existing("AMZ:B:2023-02-10", "Amazon", date(2, 10), -2000),
+				existing("", "Amazon", date(2, 10), -700),
+				existing("", "Costco", date(2, 10), -900),
+			},
+			months: []string{"2023-02 -2000 -2700 0 1"},
+			extra:  1,
+		},
+		{
+			// Transactions match by import ID even if their amount was
+			// edited, which shows as a difference.
+			name:    "edited amount",
+			charges: []*models.SaveTransaction{charge("AMZ:B:2023-02-10", date(2, 10), -2000)},
+			all:     []*models.TransactionDetail{existing("AMZ:B:2023-02-10", "Amazon", date(2, 12), -2500)},
+			months:  []string{"2023-02 -2000 -2500 0 0"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := New(tc.charges, tc.all, retailer, 3)
+			var months []string
+			for _, m := range r.Months {
+				months = append(months, fmt.Sprintf("%s %d %d %d %d", m.Month, m.Charged, m.YNAB, m.UnmatchedCharges, m.UnmatchedYNAB))
+			}
+			if !reflect.DeepEqual(months, tc.months) {
+				t.Errorf("New() months = %q, want %q", months, tc.months)
+			}
+			if len(r.Unmatched) != tc.unmatched || len(r.Extra) != tc.extra {
+				t.Errorf("New() has %d unmatched charges and %d extra transactions, want %d and %d", len(r.Unmatched), len(r.Extra), tc.unmatched, tc.extra)
+			}
+		})
+	}
+}
+
+func TestSince(t *testing.T) {
+	charges := []*models.SaveTransaction{charge("", date(2, 10), -1), charge("", date(2, 1), -1)}
+	if got, want := Since(charges, 3), *date(1, 29); got != want {
+		t.Errorf("Since() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/dbinit/ynab-amazon-import/journal"
+	"github.com/dbinit/ynab-amazon-import/orders"
+	"github.com/dbinit/ynab-amazon-import/sink"
+	"github.com/dbinit/ynab-amazon-import/ynabsync"
+)
+
+// Environment variables holding the actual-http-api key and the Firefly III
+// personal access token.
+const (
+	actualKeyEnv    = "ACTUAL_API_KEY"
+	fireflyTokenEnv = "FIREFLY_TOKEN"
+)
+
+// sinkFlags registers the flags of the sinks for other budgeting tools.
+func (o *options) sinkFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.to, "to", "ynab", "Budgeting tool to import into: ynab, actual or firefly")
+	fs.StringVar(&o.actualURL, "actual_url", "", "Base URL of an actual-http-api server for Actual Budget; the API key is read from $"+actualKeyEnv)
+	fs.StringVar(&o.actualBudget, "actual_budget", "", "Actual Budget sync ID of the budget")
+	fs.StringVar(&o.actualAccount, "actual_account", "", "Actual Budget account ID")
+	fs.StringVar(&o.fireflyURL, "firefly_url", "", "Base URL of a Firefly III server; the personal access token is read from $"+fireflyTokenEnv)
+	fs.StringVar(&o.fireflyAccount, "firefly_account", "", "Firefly III ID of the asset or liability account that paid for the orders")
+}
+
+// newSink returns the sink selected by the to flag, after checking its flags.
+func (o *options) newSink(ctx context.Context, fs *flag.FlagSet) (sink.Sink, error) {
+	switch o.to {
+	case "ynab":
+		if err := require(fs, "budget", "account"); err != nil {
+			return nil, err
+		}
+		if err := o.resolveToken(); err != nil {
+			return nil, err
+		}
+		if o.batchSize <= 0 {
+			return nil, fmt.Errorf("invalid batch size %d", o.batchSize)
+		}
+		return &ynabSink{o: o, fs: fs}, nil
+	case "actual":
+		if err := require(fs, "actual_url", "actual_budget", "actual_account"); err != nil {
+			return nil, err
+		}
+		b, err := o.newBuilder(ctx, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &sink.Actual{
+			URL:     o.actualURL,
+			Key:     os.Getenv(actualKeyEnv),
+			Budget:  o.actualBudget,
+			Account: o.actualAccount,
+			Builder: b,
+			Client:  &http.Client{Timeout: time.Minute},
+		}, nil
+	case "firefly":
+		if err := require(fs, "firefly_url", "firefly_account"); err != nil {
+			return nil, err
+		}
+		token := os.Getenv(fireflyTokenEnv)
+		if token == "" {
+			return nil, fmt.Errorf("no Firefly III token: set $%s", fireflyTokenEnv)
+		}
+		b, err := o.newBuilder(ctx, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &sink.Firefly{
+			URL:     o.fireflyURL,
+			Token:   token,
+			Account: o.fireflyAccount,
+			Builder: b,
+			Client:  &http.Client{Timeout: time.Minute},
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown budgeting tool %q", o.to)
+}
+
+// ynabSink posts transactions to a YNAB account, recording the run in the
+// journal.
+type ynabSink struct {
+	o  *options
+	fs *flag.FlagSet
+}
+
+func (s *ynabSink) Write(ctx context.Context, odm map[string]*orders.Order) error {
+	o := s.o
+	budgetID, accountID, err := budgetAccount(ctx, o.budget, o.account, o.metaCache())
+	if err != nil {
+		return err
+	}
+	txns, err := o.buildTxns(ctx, odm, budgetID, accountID)
+	if err != nil {
+		return err
+	}
+
+	p := &ynabsync.Poster{
+		Client:     o.ynabClient(),
+		BudgetID:   *budgetID,
+		BatchSize:  o.batchSize,
+		MaxRetries: o.maxRetries,
+		Checkpoint: o.checkpoint,
+		Logf:       log.Printf,
+	}
+	var entry *journal.Entry
+	if o.journal != "" {
+		// Sources with a single export have no items file, and invoices are
+		// optional.
+		if entry, err = journal.NewEntry(budgetID.String(), accountID.String(), setFlags(s.fs), o.orders, o.items, o.invoices); err != nil {
+			return err
+		}
+	}
+	res, err := p.Post(ctx, txns)
+	if res != nil {
+		o.recordTransactions(budgetID.String(), res.Transactions)
+	}
+	if entry != nil && res != nil {
+		entry.Result = *res
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		if jerr := (&journal.Journal{Dir: o.journal}).Create(entry); jerr != nil {
+			log.Printf("failed to save journal: %v", jerr)
+		} else {
+			log.Printf("journal run %s saved to %q", entry.Run, o.journal)
+		}
+	}
+	if err != nil {
+		if o.checkpoint != "" {
+			log.Printf("rerun with the same flags to resume from %q", o.checkpoint)
+		}
+		return err
+	}
+	log.Printf("%d transactions created, %d duplicates skipped", len(res.TransactionIDs), len(res.DuplicateImportIDs))
+	return nil
+}
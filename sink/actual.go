@@ -0,0 +1,119 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/dbinit/ynab-amazon-import/orders"
+	"github.com/dbinit/ynab-amazon-import/txn"
+)
+
+// Actual imports orders into an Actual Budget account through
+// actual-http-api, which exposes Actual's import over HTTP. Actual skips
+// transactions whose imported ID it has already seen.
+type Actual struct {
+	// URL is the base URL of the actual-http-api server, and Key its API
+	// key, if it needs one.
+	URL string
+	Key string
+
+	// Budget is the sync ID of the budget and Account the ID of the account.
+	Budget  string
+	Account string
+
+	Builder *txn.Builder
+
+	// Client makes the requests, or http.DefaultClient if it's nil.
+	Client *http.Client
+}
+
+// actualTransaction is a transaction in Actual's import format, with amounts
+// in cents.
+type actualTransaction struct {
+	Account         string         `json:"account,omitempty"`
+	Date            string         `json:"date"`
+	Amount          int64          `json:"amount"`
+	PayeeName       string         `json:"payee_name,omitempty"`
+	ImportedID      string         `json:"imported_id,omitempty"`
+	Notes           string         `json:"notes,omitempty"`
+	Cleared         bool           `json:"cleared"`
+	Subtransactions []*actualSplit `json:"subtransactions,omitempty"`
+}
+
+// actualSplit is a split line of an Actual transaction.
+type actualSplit struct {
+	Amount int64  `json:"amount"`
+	Notes  string `json:"notes,omitempty"`
+}
+
+// actualImportResponse is the response of actual-http-api's import.
+type actualImportResponse struct {
+	Data struct {
+		Added   []string `json:"added"`
+		Updated []string `json:"updated"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	} `json:"data"`
+}
+
+func (s *Actual) Write(ctx context.Context, odm map[string]*orders.Order) error {
+	entries, err := s.Builder.Entries(odm, "")
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("nothing to import")
+	}
+	var req struct {
+		Transactions []*actualTransaction `json:"transactions"`
+	}
+	for _, en := range entries {
+		t := &actualTransaction{
+			Account:    s.Account,
+			Date:       en.Date.Format("2006-01-02"),
+			Amount:     en.Total / 10,
+			PayeeName:  en.Payee,
+			ImportedID: en.ImportID,
+			Notes:      en.Memo,
+			Cleared:    en.Cleared,
+		}
+		if len(en.Postings) > 1 {
+			for _, p := range en.Postings {
+				t.Subtransactions = append(t.Subtransactions, &actualSplit{Amount: -p.Amount / 10, Notes: p.Memo})
+			}
+		}
+		req.Transactions = append(req.Transactions, t)
+	}
+
+	u := fmt.Sprintf("%s/v1/budgets/%s/accounts/%s/transactions/import", strings.TrimSuffix(s.URL, "/"), url.PathEscape(s.Budget), url.PathEscape(s.Account))
+	header := http.Header{}
+	if s.Key != "" {
+		header.Set("x-api-key", s.Key)
+	}
+	var resp actualImportResponse
+	if err := postJSON(ctx, client(s.Client), u, header, &req, &resp); err != nil {
+		return err
+	}
+	for _, e := range resp.Data.Errors {
+		log.Printf("Actual Budget import error: %s", e.Message)
+	}
+	if n := len(resp.Data.Errors); n > 0 {
+		return fmt.Errorf("Actual Budget failed to import %d transactions", n)
+	}
+	log.Printf("%d transactions added, %d updated, %d already imported",
+		len(resp.Data.Added), len(resp.Data.Updated), len(entries)-len(resp.Data.Added)-len(resp.Data.Updated))
+	return nil
+}
+
+// client returns c, or http.DefaultClient if c is nil.
+func client(c *http.Client) *http.Client {
+	if c == nil {
+		return http.DefaultClient
+	}
+	return c
+}
@@ -0,0 +1,131 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/dbinit/ynab-amazon-import/export"
+	"github.com/dbinit/ynab-amazon-import/orders"
+	"github.com/dbinit/ynab-amazon-import/txn"
+)
+
+// Firefly creates Firefly III withdrawals from an asset or liability account.
+// Each order shipment is a transaction group with a split per item, and
+// Firefly's duplicate hash check skips shipments already imported.
+type Firefly struct {
+	// URL is the base URL of the Firefly III server, and Token a personal
+	// access token.
+	URL   string
+	Token string
+
+	// Account is the ID of the asset or liability account that paid for the
+	// orders.
+	Account string
+
+	Builder *txn.Builder
+
+	// Client makes the requests, or http.DefaultClient if it's nil.
+	Client *http.Client
+}
+
+// fireflyGroup is a Firefly III transaction group.
+type fireflyGroup struct {
+	ErrorIfDuplicateHash bool                  `json:"error_if_duplicate_hash"`
+	ApplyRules           bool                  `json:"apply_rules"`
+	GroupTitle           string                `json:"group_title,omitempty"`
+	Transactions         []*fireflyTransaction `json:"transactions"`
+}
+
+// fireflyTransaction is a split of a Firefly III transaction group, with a
+// positive decimal amount.
+type fireflyTransaction struct {
+	Type            string `json:"type"`
+	Date            string `json:"date"`
+	Amount          string `json:"amount"`
+	Description     string `json:"description"`
+	SourceID        string `json:"source_id"`
+	DestinationName string `json:"destination_name"`
+	CategoryName    string `json:"category_name,omitempty"`
+	Notes           string `json:"notes,omitempty"`
+	ExternalID      string `json:"external_id,omitempty"`
+}
+
+func (s *Firefly) Write(ctx context.Context, odm map[string]*orders.Order) error {
+	entries, err := s.Builder.Entries(odm, "")
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("nothing to import")
+	}
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+s.Token)
+	var created, duplicates int
+	for _, en := range entries {
+		err := postJSON(ctx, client(s.Client), strings.TrimSuffix(s.URL, "/")+"/api/v1/transactions", header, s.group(en), nil)
+		var herr *HTTPError
+		if errors.As(err, &herr) && herr.Status == http.StatusUnprocessableEntity && strings.Contains(herr.Body, "Duplicate") {
+			duplicates++
+			continue
+		}
+		if err != nil {
+			log.Printf("%d transactions created before the failure", created)
+			return fmt.Errorf("failed to create %s: %w", en.ImportID, err)
+		}
+		created++
+	}
+	log.Printf("%d transactions created, %d duplicates skipped", created, duplicates)
+	return nil
+}
+
+// group returns the transaction group of an entry. Withdrawal splits must be
+// positive, so entries with promotions exceeding shipping are created whole.
+func (s *Firefly) group(en *txn.Entry) *fireflyGroup {
+	split := func(amount int64, category, notes string) *fireflyTransaction {
+		if notes == "" {
+			notes = en.Memo
+		}
+		description := notes
+		if description == "" {
+			description = en.Payee
+		}
+		return &fireflyTransaction{
+			Type:            "withdrawal",
+			Date:            en.Date.Format("2006-01-02"),
+			Amount:          export.Decimal(amount),
+			Description:     description,
+			SourceID:        s.Account,
+			DestinationName: en.Payee,
+			CategoryName:    category,
+			Notes:           notes,
+			ExternalID:      en.ImportID,
+		}
+	}
+	g := &fireflyGroup{ErrorIfDuplicateHash: true, ApplyRules: true}
+	whole := len(en.Postings) <= 1
+	for _, p := range en.Postings {
+		if p.Amount <= 0 {
+			whole = true
+		}
+	}
+	if whole {
+		var category string
+		if len(en.Postings) == 1 {
+			category = en.Postings[0].Category
+		}
+		g.Transactions = append(g.Transactions, split(-en.Total, category, en.Memo))
+		return g
+	}
+	g.GroupTitle = en.Memo
+	if g.GroupTitle == "" {
+		g.GroupTitle = en.OrderID
+	}
+	for _, p := range en.Postings {
+		g.Transactions = append(g.Transactions, split(p.Amount, p.Category, p.Memo))
+	}
+	return g
+}
@@ -0,0 +1,123 @@
+// Package sink writes merged orders to a budgeting tool or a file: Actual
+// Budget through actual-http-api, Firefly III through its API, or a file
+// written by the export package. YNAB is synced by the ynabsync package.
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dbinit/ynab-amazon-import/export"
+	"github.com/dbinit/ynab-amazon-import/orders"
+	"github.com/dbinit/ynab-amazon-import/txn"
+)
+
+// Sink writes merged orders to a budgeting tool or a file.
+type Sink interface {
+	Write(ctx context.Context, odm map[string]*orders.Order) error
+}
+
+// File writes transactions to a file for YNAB's file import, or entries to a
+// plain-text accounting journal.
+type File struct {
+	// Builder builds the transactions, with export.AccountID as the account
+	// for the transaction file formats.
+	Builder *txn.Builder
+
+	// Format is one of export.Formats or export.LedgerFormats.
+	Format string
+
+	// Output is the file to write, or "" or "-" for stdout.
+	Output string
+
+	// Account is the statement account of formats that have one, e.g. the
+	// retailer.
+	Account string
+
+	// Payment and Expense are the plain-text accounting accounts that paid
+	// for the orders and of items that no rule assigns an account.
+	Payment string
+	Expense string
+}
+
+func (s *File) Write(ctx context.Context, odm map[string]*orders.Order) error {
+	if write, ok := export.LedgerFormats[s.Format]; ok {
+		entries, err := s.Builder.Entries(odm, s.Expense)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return fmt.Errorf("nothing to export")
+		}
+		return export.WriteFile(s.Output, func(w io.Writer) error { return write(w, entries, s.Payment) })
+	}
+	write, ok := export.Formats[s.Format]
+	if !ok {
+		return fmt.Errorf("unknown export format %q", s.Format)
+	}
+	txns, err := s.Builder.Transactions(odm)
+	if err != nil {
+		return err
+	}
+	if len(txns) == 0 {
+		return fmt.Errorf("nothing to export")
+	}
+	if err := txn.Check(txns, time.Now()); err != nil {
+		return err
+	}
+	return export.WriteFile(s.Output, func(w io.Writer) error { return write(w, txns, s.Account) })
+}
+
+// HTTPError is an unexpected HTTP response from a budgeting tool.
+type HTTPError struct {
+	Method, URL string
+	Status      int
+	Body        string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%s %s: %d %s: %s", e.Method, e.URL, e.Status, http.StatusText(e.Status), e.Body)
+}
+
+// postJSON posts a JSON request and decodes the JSON response into out, if it
+// isn't nil. Responses other than 2xx are returned as *HTTPError.
+func postJSON(ctx context.Context, c *http.Client, url string, header http.Header, in, out any) error {
+	b, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("json.Marshal(): %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("http.NewRequestWithContext(%q): %w", url, err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.Do(req)
+	if err != nil {
+		return fmt.Errorf("POST %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("POST %s: %w", url, err)
+	}
+	if resp.StatusCode/100 != 2 {
+		return &HTTPError{Method: http.MethodPost, URL: url, Status: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response of POST %s: %w", url, err)
+	}
+	return nil
+}
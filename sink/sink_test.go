@@ -0,0 +1,247 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dbinit/ynab-amazon-import/orders"
+	"github.com/dbinit/ynab-amazon-import/txn"
+	"github.com/go-openapi/strfmt"
+)
+
+// testOrders returns a single item order and an order split into two items
+// and a shipping charge.
+func testOrders() map[string]*orders.Order {
+	retailer := orders.Retailer{Payee: "Amazon", ImportPrefix: "AMZ:"}
+	date := func(s string) strfmt.Date {
+		d, _ := time.Parse("2006-01-02", s)
+		return strfmt.Date(d)
+	}
+	single := &orders.Order{
+		ID:           "111-0000001-0000001",
+		ShipmentDate: date("2023-01-02"),
+		Retailer:     retailer,
+		TotalCharged: -12340,
+		Items:        []*orders.Item{{Title: "Skillet", Seller: "Lodge", Quantity: 1, Total: -12340}},
+	}
+	split := &orders.Order{
+		ID:             "111-0000002-0000002",
+		ShipmentDate:   date("2023-01-03"),
+		Retailer:       retailer,
+		ShippingCharge: -1000,
+		TotalCharged:   -16000,
+		Items: []*orders.Item{
+			{Title: "Batteries", Seller: "Amazon.com", Quantity: 1, Total: -10000},
+			{Title: "Widget", Seller: "Amazon.com", Quantity: 1, Total: -5000},
+		},
+	}
+	return map[string]*orders.Order{single.Key(): single, split.Key(): split}
+}
+
+// request is a request received by a stand-in server.
+type request struct {
+	path   string
+	header http.Header
+	body   []byte
+}
+
+// standIn starts a stand-in server answering each request with the next of
+// the statuses and bodies, and recording the requests.
+func standIn(t *testing.T, statuses []int, bodies []string) (*httptest.Server, *[]*request) {
+	t.Helper()
+	var reqs []*request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("reading request: %v", err)
+		}
+		i := len(reqs)
+		reqs = append(reqs, &request{path: r.URL.EscapedPath(), header: r.Header, body: b})
+		if i >= len(statuses) {
+			i = len(statuses) - 1
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statuses[i])
+		io.WriteString(w, bodies[i])
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &reqs
+}
+
+func TestActualWrite(t *testing.T) {
+	srv, reqs := standIn(t, []int{http.StatusOK}, []string{`{"data":{"added":["a","b"],"updated":[],"errors":[]}}`})
+	s := &Actual{URL: srv.URL + "/", Key: "key", Budget: "budget 1", Account: "acct", Builder: &txn.Builder{Cleared: "cleared"}}
+	if err := s.Write(context.Background(), testOrders()); err != nil {
+		t.Fatalf("Write() = %v", err)
+	}
+	if len(*reqs) != 1 {
+		t.Fatalf("got %d requests, want 1", len(*reqs))
+	}
+	r := (*reqs)[0]
+	if want := "/v1/budgets/budget%201/accounts/acct/transactions/import"; r.path != want {
+		t.Errorf("path = %q, want %q", r.path, want)
+	}
+	if got := r.header.Get("x-api-key"); got != "key" {
+		t.Errorf("x-api-key = %q, want %q", got, "key")
+	}
+	var body struct {
+		Transactions []*actualTransaction `json:"transactions"`
+	}
+	if err := json.Unmarshal(r.body, &body); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	if len(body.Transactions) != 2 {
+		t.Fatalf("got %d transactions, want 2", len(body.Transactions))
+	}
+
+	// Amounts are in cents, and single item orders aren't split.
+	single, split := body.Transactions[0], body.Transactions[1]
+	if single.Amount != -1234 || single.Date != "2023-01-02" || single.ImportedID != "AMZ:111-0000001-0000001:2023-01-02" || !single.Cleared {
+		t.Errorf("single transaction = %+v", single)
+	}
+	if len(single.Subtransactions) != 0 {
+		t.Errorf("single transaction has %d splits, want 0", len(single.Subtransactions))
+	}
+	if split.Amount != -1600 || split.Account != "acct" {
+		t.Errorf("split transaction = %+v", split)
+	}
+	var amounts []int64
+	for _, st := range split.Subtransactions {
+		amounts = append(amounts, st.Amount)
+	}
+	if want := []int64{-100, -1000, -500}; !equal(amounts, want) {
+		t.Errorf("split amounts = %v, want %v", amounts, want)
+	}
+}
+
+func TestActualWriteErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		status int
+		body   string
+		want   string
+		http   bool
+	}{
+		{"import errors", http.StatusOK, `{"data":{"added":[],"updated":[],"errors":[{"message":"bad date"}]}}`, "failed to import 1 transactions", false},
+		{"unauthorized", http.StatusUnauthorized, `{"error":"unauthorized"}`, "401 Unauthorized", true},
+		{"bad response", http.StatusOK, `not json`, "failed to parse response", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, _ := standIn(t, []int{tc.status}, []string{tc.body})
+			s := &Actual{URL: srv.URL, Budget: "b", Account: "a", Builder: &txn.Builder{}}
+			err := s.Write(context.Background(), testOrders())
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("Write() = %v, want an error containing %q", err, tc.want)
+			}
+			var herr *HTTPError
+			if errors.As(err, &herr) != tc.http {
+				t.Errorf("Write() = %T, HTTP error %v", err, tc.http)
+			}
+			if tc.http && herr.Status != tc.status {
+				t.Errorf("HTTPError.Status = %d, want %d", herr.Status, tc.status)
+			}
+		})
+	}
+}
+
+func TestFireflyWrite(t *testing.T) {
+	// The second order is a duplicate, which Firefly rejects with a 422.
+	srv, reqs := standIn(t,
+		[]int{http.StatusOK, http.StatusUnprocessableEntity},
+		[]string{`{"data":{}}`, `{"message":"Duplicate of transaction #1.","errors":{"transactions.0.description":["Duplicate of transaction #1."]}}`})
+	s := &Firefly{URL: srv.URL, Token: "tok", Account: "7", Builder: &txn.Builder{}}
+	if err := s.Write(context.Background(), testOrders()); err != nil {
+		t.Fatalf("Write() = %v", err)
+	}
+	if len(*reqs) != 2 {
+		t.Fatalf("got %d requests, want 2", len(*reqs))
+	}
+	var groups []*fireflyGroup
+	for _, r := range *reqs {
+		if r.path != "/api/v1/transactions" {
+			t.Errorf("path = %q, want /api/v1/transactions", r.path)
+		}
+		if got := r.header.Get("Authorization"); got != "Bearer tok" {
+			t.Errorf("Authorization = %q, want %q", got, "Bearer tok")
+		}
+		g := &fireflyGroup{}
+		if err := json.Unmarshal(r.body, g); err != nil {
+			t.Fatalf("request body: %v", err)
+		}
+		groups = append(groups, g)
+	}
+
+	// A single item order is a single withdrawal, and other orders a group
+	// with a positive split per posting.
+	single, split := groups[0], groups[1]
+	if !single.ErrorIfDuplicateHash || len(single.Transactions) != 1 {
+		t.Fatalf("single group = %+v", single)
+	}
+	if tr := single.Transactions[0]; tr.Type != "withdrawal" || tr.Amount != "12.34" || tr.SourceID != "7" || tr.Date != "2023-01-02" {
+		t.Errorf("single withdrawal = %+v", tr)
+	}
+	var amounts []string
+	for _, tr := range split.Transactions {
+		amounts = append(amounts, tr.Amount)
+		if tr.ExternalID != "AMZ:111-0000002-0000002:2023-01-03" {
+			t.Errorf("ExternalID = %q", tr.ExternalID)
+		}
+	}
+	if want := "1.00 10.00 5.00"; strings.Join(amounts, " ") != want {
+		t.Errorf("split amounts = %v, want %s", amounts, want)
+	}
+	if split.GroupTitle == "" {
+		t.Error("split group has no title")
+	}
+}
+
+func TestFireflyWriteErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"validation error", http.StatusUnprocessableEntity, `{"message":"The amount is invalid."}`},
+		{"server error", http.StatusInternalServerError, `oops`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, reqs := standIn(t, []int{tc.status}, []string{tc.body})
+			s := &Firefly{URL: srv.URL, Token: "tok", Account: "7", Builder: &txn.Builder{}}
+			err := s.Write(context.Background(), testOrders())
+			var herr *HTTPError
+			if !errors.As(err, &herr) {
+				t.Fatalf("Write() = %v, want an *HTTPError", err)
+			}
+			if herr.Status != tc.status || herr.Body != tc.body || herr.Method != http.MethodPost {
+				t.Errorf("HTTPError = %+v", herr)
+			}
+			if !strings.Contains(err.Error(), http.StatusText(tc.status)) {
+				t.Errorf("Write() = %q, want the status text", err)
+			}
+			// Writing stops at the first failure.
+			if len(*reqs) != 1 {
+				t.Errorf("got %d requests, want 1", len(*reqs))
+			}
+		})
+	}
+}
+
+// equal reports whether two amount lists are equal.
+func equal(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
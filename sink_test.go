@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/dbinit/ynab-amazon-import/client"
+	"github.com/dbinit/ynab-amazon-import/journal"
+	"github.com/dbinit/ynab-amazon-import/models"
+	"github.com/dbinit/ynab-amazon-import/orderdb"
+)
+
+const (
+	testBudgetID  = "11111111-1111-1111-1111-111111111111"
+	testAccountID = "22222222-2222-2222-2222-222222222222"
+)
+
+// ynabStandIn starts a stand-in YNAB API with a budget "Home" and an account
+// "Visa", which creates the transactions posted to it. It returns the
+// server and the posted transactions.
+func ynabStandIn(t *testing.T) (*httptest.Server, *[]*models.SaveTransaction) {
+	t.Helper()
+	var posted []*models.SaveTransaction
+	budget := "/v1/budgets/" + testBudgetID
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/budgets":
+			fmt.Fprintf(w, `{"data":{"budgets":[{"id":%q,"name":"Home","last_modified_on":"2023-01-01T00:00:00Z"}]}}`, testBudgetID)
+		case budget + "/accounts":
+			fmt.Fprintf(w, `{"data":{"server_knowledge":1,"accounts":[{"id":%q,"name":"Visa","type":"creditCard","on_budget":true,"closed":false,"balance":0,"cleared_balance":0,"uncleared_balance":0,"transfer_payee_id":"33333333-3333-3333-3333-333333333333","deleted":false}]}}`, testAccountID)
+		case budget + "/categories":
+			fmt.Fprint(w, `{"data":{"server_knowledge":1,"category_groups":[]}}`)
+		case budget + "/payees":
+			fmt.Fprint(w, `{"data":{"server_knowledge":1,"payees":[]}}`)
+		case budget + "/transactions":
+			b, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Errorf("reading request: %v", err)
+			}
+			var req models.PostTransactionsWrapper
+			if err := json.Unmarshal(b, &req); err != nil {
+				t.Errorf("request body: %v", err)
+			}
+			type created struct {
+				ID       string `json:"id"`
+				ImportID string `json:"import_id"`
+				Date     string `json:"date"`
+				Amount   int64  `json:"amount"`
+			}
+			var resp struct {
+				Data struct {
+					TransactionIDs  []string   `json:"transaction_ids"`
+					Transactions    []*created `json:"transactions"`
+					ServerKnowledge int64      `json:"server_knowledge"`
+				} `json:"data"`
+			}
+			for _, st := range req.Transactions {
+				id := fmt.Sprintf("t%d", len(posted)+1)
+				posted = append(posted, st)
+				resp.Data.TransactionIDs = append(resp.Data.TransactionIDs, id)
+				resp.Data.Transactions = append(resp.Data.Transactions, &created{id, st.ImportID, st.Date.String(), *st.Amount})
+			}
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(&resp)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &posted
+}
+
+// runCommand runs a command as main does, with the YNAB API URL overridden.
+func runCommand(t *testing.T, ynabURL string, args ...string) error {
+	t.Helper()
+	def := client.Default
+	t.Cleanup(func() { client.Default = def })
+	c := findCommand(args[0])
+	o := &options{}
+	fs := c.flagSet(o)
+	if err := fs.Parse(append(args[1:], "-config", "", "-ynab_url", ynabURL)); err != nil {
+		return err
+	}
+	if err := o.useYNABURL(); err != nil {
+		return err
+	}
+	return c.run(context.Background(), fs, o)
+}
+
+func TestImportYNAB(t *testing.T) {
+	srv, posted := ynabStandIn(t)
+	dir := t.TempDir()
+	db := filepath.Join(dir, "orders.json")
+	err := runCommand(t, srv.URL+"/v1", "import",
+		"-token", "test", "-budget", "Home", "-account", "Visa",
+		"-orders", "testdata/orders.csv", "-items", "testdata/items.csv",
+		"-cache", "", "-journal", filepath.Join(dir, "journal"), "-db", db)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+
+	// The single item order is a single transaction, and the other is split
+	// into its shipping charge and items, all in milliunits.
+	if len(*posted) != 2 {
+		t.Fatalf("posted %d transactions, want 2", len(*posted))
+	}
+	single, split := (*posted)[0], (*posted)[1]
+	if *single.Amount != -12340 || single.ImportID != "AMZ:111-0000001-0000001:2023-01-02" || single.AccountID.String() != testAccountID {
+		t.Errorf("single transaction = %+v", single)
+	}
+	if len(single.Subtransactions) != 0 {
+		t.Errorf("single transaction has %d splits", len(single.Subtransactions))
+	}
+	var amounts []int64
+	for _, st := range split.Subtransactions {
+		amounts = append(amounts, *st.Amount)
+	}
+	if *split.Amount != -16000 || fmt.Sprint(amounts) != "[-1000 -10000 -5000]" {
+		t.Errorf("split transaction amount %d, splits %v", *split.Amount, amounts)
+	}
+
+	// The run is journaled and its transactions linked to their orders.
+	entries, err := (&journal.Journal{Dir: filepath.Join(dir, "journal")}).List()
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("List() = %d entries, %v; want 1", len(entries), err)
+	}
+	if n := len(entries[0].TransactionIDs); n != 2 {
+		t.Errorf("journal has %d transaction IDs, want 2", n)
+	}
+	odb, err := orderdb.Open(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, r := range odb.Records {
+		if len(r.Transactions) != 1 {
+			t.Errorf("order %s has %d transactions, want 1", id, len(r.Transactions))
+		}
+	}
+}
+
+func TestYNABURL(t *testing.T) {
+	for _, tc := range []struct {
+		url string
+		ok  bool
+	}{
+		{"", true},
+		{"http://localhost:8080/v1", true},
+		{"localhost:8080", false},
+		{"/v1", false},
+	} {
+		t.Run(tc.url, func(t *testing.T) {
+			def := client.Default
+			t.Cleanup(func() { client.Default = def })
+			o := &options{ynabURL: tc.url}
+			err := o.useYNABURL()
+			if (err == nil) != tc.ok {
+				t.Fatalf("useYNABURL() = %v, want ok %v", err, tc.ok)
+			}
+			if tc.ok && tc.url != "" && client.Default == def {
+				t.Error("useYNABURL() didn't replace the default client")
+			}
+			if tc.url == "" && client.Default != def {
+				t.Error("useYNABURL() replaced the default client without a URL")
+			}
+		})
+	}
+}
+
+func TestImportDryRun(t *testing.T) {
+	// Only dry runs into YNAB need its budget, account and token, and none
+	// needs the other tools' flags or credentials.
+	for _, tc := range []struct {
+		to string
+		ok bool
+	}{
+		{"ynab", false},
+		{"actual", true},
+		{"firefly", true},
+		{"lunchmoney", false},
+	} {
+		t.Run(tc.to, func(t *testing.T) {
+			t.Setenv("YNAB_TOKEN", "")
+			err := runCommand(t, "", "import", "-dry_run", "-to", tc.to,
+				"-orders", "testdata/orders.csv", "-items", "testdata/items.csv", "-journal", "")
+			if (err == nil) != tc.ok {
+				t.Errorf("import -dry_run -to %s = %v, want ok %v", tc.to, err, tc.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+// Package spending summarizes orders into a spending report by month, seller,
+// categorization rule and payment instrument, with shipping, promotion and
+// fee totals and the top items, written as text, CSV or a self-contained HTML
+// page with charts.
+//
+// Unlike the orders model, report amounts are spending, so charges are
+// positive and refunds negative.
+package spending
+
+import (
+	"sort"
+	"time"
+
+	"github.com/dbinit/ynab-amazon-import/orders"
+	"github.com/dbinit/ynab-amazon-import/txn"
+)
+
+// Names of the groups of items that no rule categorizes and of charges with no
+// known payment instrument.
+const (
+	Uncategorized     = "Uncategorized"
+	UnknownInstrument = "Unknown"
+)
+
+// Report is a spending report.
+type Report struct {
+	// From and To are the first and last shipment dates.
+	From, To string
+
+	Orders int
+	Items  int64
+
+	// Spent is the total charged, which is the item totals plus Shipping
+	// and Fees less Promotions.
+	Spent      int64
+	Shipping   int64
+	Promotions int64
+	Fees       int64
+	Tax        int64
+
+	Months      []*Group
+	Sellers     []*Group
+	Categories  []*Group
+	Instruments []*Group
+	TopItems    []*Group
+}
+
+// Group is the spending of a month, seller, category rule, payment instrument
+// or item. Orders counts order shipments and Items the quantity of items.
+type Group struct {
+	Name   string
+	Orders int
+	Items  int64
+	Amount int64
+}
+
+// groups accumulates groups by name, counting each order shipment once.
+type groups struct {
+	m    map[string]*Group
+	seen map[string]bool
+}
+
+func newGroups() *groups {
+	return &groups{m: make(map[string]*Group), seen: make(map[string]bool)}
+}
+
+// add adds an amount and item quantity of an order shipment to a group.
+func (gs *groups) add(name string, od *orders.Order, items, amount int64) {
+	g := gs.m[name]
+	if g == nil {
+		g = &Group{Name: name}
+		gs.m[name] = g
+	}
+	if k := name + "\x00" + od.Key(); !gs.seen[k] {
+		gs.seen[k] = true
+		g.Orders++
+	}
+	g.Items += items
+	g.Amount += amount
+}
+
+// byName returns the groups sorted by name.
+func (gs *groups) byName() []*Group {
+	var s []*Group
+	for _, k := range orders.SortedKeys(gs.m) {
+		s = append(s, gs.m[k])
+	}
+	return s
+}
+
+// byAmount returns the groups sorted by amount, largest first, then by name.
+func (gs *groups) byAmount() []*Group {
+	s := gs.byName()
+	sort.SliceStable(s, func(i, j int) bool { return s[i].Amount > s[j].Amount })
+	return s
+}
+
+// New builds a report from orders. Items are categorized by the name of the
+// first rule assigning them a category or account, and the top items are
+// limited to top, or all items if top is 0.
+func New(odm map[string]*orders.Order, rules []*txn.Rule, top int) *Report {
+	r := &Report{}
+	months, sellers, categories, instruments, items := newGroups(), newGroups(), newGroups(), newGroups(), newGroups()
+	for _, k := range orders.SortedKeys(odm) {
+		od := odm[k]
+		date := od.ShipmentDate.String()
+		if r.From == "" || date < r.From {
+			r.From = date
+		}
+		if date > r.To {
+			r.To = date
+		}
+		r.Orders++
+		r.Spent -= od.TotalCharged
+		r.Shipping -= od.ShippingCharge
+		r.Promotions += od.TotalPromotions
+		r.Fees -= od.FeesTotal()
+		r.Tax -= od.TaxCharged
+
+		var quantity int64
+		for _, it := range od.Items {
+			quantity += it.Quantity
+			seller := it.Seller
+			if seller == "" {
+				seller = od.Retailer.Payee
+			}
+			sellers.add(seller, od, it.Quantity, -it.Total)
+			category := Uncategorized
+			if rule := txn.CategoryRule(rules, od, it); rule != nil {
+				category = rule.Name
+			}
+			categories.add(category, od, it.Quantity, -it.Total)
+			if it.Seller != orders.MissingSeller {
+				key := it.ASIN
+				if key == "" {
+					key = it.Title
+				}
+				items.add(key, od, it.Quantity, -it.Total)
+				items.m[key].Name = it.Title
+			}
+		}
+		r.Items += quantity
+		months.add(time.Time(od.ShipmentDate).Format("2006-01"), od, quantity, -od.TotalCharged)
+		if len(od.Payments) == 0 {
+			instruments.add(UnknownInstrument, od, 0, -od.TotalCharged)
+		}
+		for _, p := range od.Payments {
+			instruments.add(p.Instrument, od, 0, -p.Amount)
+		}
+	}
+
+	r.Months = months.byName()
+	r.Sellers = sellers.byAmount()
+	r.Categories = categories.byAmount()
+	r.Instruments = instruments.byAmount()
+	r.TopItems = items.byAmount()
+	if top > 0 && len(r.TopItems) > top {
+		r.TopItems = r.TopItems[:top]
+	}
+	return r
+}
+
+// Section is a titled breakdown of a report.
+type Section struct {
+	// Key names the section in CSV output.
+	Key    string
+	Title  string
+	Groups []*Group
+}
+
+// Sections returns the report's breakdowns in display order.
+func (r *Report) Sections() []*Section {
+	return []*Section{
+		{"month", "By month", r.Months},
+		{"seller", "By seller", r.Sellers},
+		{"category", "By category rule", r.Categories},
+		{"instrument", "By payment instrument", r.Instruments},
+		{"item", "Top items", r.TopItems},
+	}
+}
+
+// Totals returns the report's totals as named amounts, in display order.
+func (r *Report) Totals() []*Group {
+	return []*Group{
+		{Name: "Spent", Orders: r.Orders, Items: r.Items, Amount: r.Spent},
+		{Name: "Shipping", Amount: r.Shipping},
+		{Name: "Promotions", Amount: r.Promotions},
+		{Name: "Fees", Amount: r.Fees},
+		{Name: "Tax", Amount: r.Tax},
+	}
+}
@@ -0,0 +1,195 @@
+package spending
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dbinit/ynab-amazon-import/orders"
+)
+
+// share returns an amount as a percentage of the total spent, or "" if
+// nothing was spent.
+func (r *Report) share(amount int64) string {
+	if r.Spent <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.1f%%", float64(amount)*100/float64(r.Spent))
+}
+
+// WriteText writes the report as plain text tables.
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "Spending from %s to %s\n\n", r.From, r.To)
+	for _, g := range r.Totals() {
+		fmt.Fprintf(tw, "%s\t%s\t\n", g.Name, orders.FormatMoney(g.Amount))
+	}
+	for _, s := range r.Sections() {
+		fmt.Fprintf(tw, "\n%s\n", s.Title)
+		fmt.Fprintln(tw, "NAME\tORDERS\tITEMS\tAMOUNT\tSHARE\t")
+		for _, g := range s.Groups {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t\n", oneLine(g.Name), g.Orders, g.Items, orders.FormatMoney(g.Amount), r.share(g.Amount))
+		}
+	}
+	return tw.Flush()
+}
+
+// WriteCSV writes the report as CSV, with a row for each total and group and
+// a column naming its section. Amounts are decimals, e.g. "12.34".
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	rows := [][]string{{"Section", "Name", "Orders", "Items", "Amount"}}
+	for _, g := range r.Totals() {
+		rows = append(rows, []string{"total", g.Name, strconv.Itoa(g.Orders), strconv.FormatInt(g.Items, 10), decimal(g.Amount)})
+	}
+	for _, s := range r.Sections() {
+		for _, g := range s.Groups {
+			rows = append(rows, []string{s.Key, g.Name, strconv.Itoa(g.Orders), strconv.FormatInt(g.Items, 10), decimal(g.Amount)})
+		}
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return fmt.Errorf("(*csv.Writer).WriteAll(): %w", err)
+	}
+	return nil
+}
+
+// decimal formats milliunits as a decimal amount, e.g. "-12.34".
+func decimal(amount int64) string {
+	return strings.Replace(orders.FormatMoney(amount), "$", "", 1)
+}
+
+// oneLine replaces line breaks and tabs, which would break a table.
+func oneLine(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ", "\t", " ").Replace(s)
+}
+
+// Dimensions of the monthly column chart, in SVG user units.
+const (
+	chartHeight = 160
+	columnWidth = 48
+)
+
+// column is a column of the monthly chart.
+type column struct {
+	X, Y, Width, Height float64
+	Label, Amount       string
+}
+
+// bar is a row of a breakdown table, with the width of its bar as a
+// percentage of the largest group.
+type bar struct {
+	*Group
+	Money, Share string
+	Width        float64
+}
+
+// htmlSection is a breakdown rendered as a table of bars.
+type htmlSection struct {
+	Title string
+	Bars  []*bar
+}
+
+// htmlData is the data of the HTML template.
+type htmlData struct {
+	Report     *Report
+	Totals     []*bar
+	ChartWidth int
+	Columns    []*column
+	Sections   []*htmlSection
+}
+
+var htmlTemplate = template.Must(template.New("spending").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Spending from {{.Report.From}} to {{.Report.To}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em auto; max-width: 60em; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+th, td { padding: 0.2em 0.6em; text-align: left; }
+td.n, th.n { text-align: right; white-space: nowrap; }
+td.bar { width: 35%; }
+td.bar div { background: #4a7bd0; height: 0.9em; }
+svg text { font-size: 11px; fill: #444; }
+svg rect { fill: #4a7bd0; }
+</style>
+</head>
+<body>
+<h1>Spending from {{.Report.From}} to {{.Report.To}}</h1>
+<table>
+{{range .Totals}}<tr><th>{{.Name}}</th><td class="n">{{.Money}}</td></tr>
+{{end}}</table>
+<h2>By month</h2>
+<svg width="{{.ChartWidth}}" height="{{.ChartHeight}}" role="img" aria-label="Spending by month">
+{{range .Columns}}<rect x="{{.X}}" y="{{.Y}}" width="{{.Width}}" height="{{.Height}}"><title>{{.Label}}: {{.Amount}}</title></rect>
+<text x="{{.X}}" y="{{$.LabelY}}">{{.Label}}</text>
+{{end}}</svg>
+{{range .Sections}}<h2>{{.Title}}</h2>
+<table>
+<tr><th>Name</th><th class="n">Orders</th><th class="n">Items</th><th class="n">Amount</th><th class="n">Share</th><th></th></tr>
+{{range .Bars}}<tr><td>{{.Name}}</td><td class="n">{{.Orders}}</td><td class="n">{{.Items}}</td><td class="n">{{.Money}}</td><td class="n">{{.Share}}</td><td class="bar"><div style="width: {{.Width}}%"></div></td></tr>
+{{end}}</table>
+{{end}}</body>
+</html>
+`))
+
+// ChartHeight returns the height of the monthly chart, including its labels.
+func (htmlData) ChartHeight() int { return chartHeight + 20 }
+
+// LabelY returns the baseline of the monthly chart's labels.
+func (htmlData) LabelY() int { return chartHeight + 14 }
+
+// WriteHTML writes the report as a self-contained HTML page, with a column
+// chart of the monthly spending and bars for each breakdown.
+func (r *Report) WriteHTML(w io.Writer) error {
+	d := &htmlData{Report: r, ChartWidth: columnWidth * len(r.Months)}
+	for _, g := range r.Totals() {
+		d.Totals = append(d.Totals, &bar{Group: g, Money: orders.FormatMoney(g.Amount)})
+	}
+	most := maxAmount(r.Months)
+	for i, g := range r.Months {
+		h := 0.0
+		if most > 0 && g.Amount > 0 {
+			h = float64(g.Amount) / float64(most) * chartHeight
+		}
+		d.Columns = append(d.Columns, &column{
+			X:      float64(i * columnWidth),
+			Y:      chartHeight - h,
+			Width:  columnWidth - 6,
+			Height: h,
+			Label:  g.Name,
+			Amount: orders.FormatMoney(g.Amount),
+		})
+	}
+	for _, s := range r.Sections() {
+		hs := &htmlSection{Title: s.Title}
+		most := maxAmount(s.Groups)
+		for _, g := range s.Groups {
+			b := &bar{Group: g, Money: orders.FormatMoney(g.Amount), Share: r.share(g.Amount)}
+			if most > 0 && g.Amount > 0 {
+				b.Width = float64(g.Amount) * 100 / float64(most)
+			}
+			hs.Bars = append(hs.Bars, b)
+		}
+		d.Sections = append(d.Sections, hs)
+	}
+	if err := htmlTemplate.Execute(w, d); err != nil {
+		return fmt.Errorf("failed to write HTML report: %w", err)
+	}
+	return nil
+}
+
+// maxAmount returns the largest amount of a list of groups.
+func maxAmount(gs []*Group) int64 {
+	var most int64
+	for _, g := range gs {
+		if g.Amount > most {
+			most = g.Amount
+		}
+	}
+	return most
+}
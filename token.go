@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+const (
+	// defaultTokenEnv is the default environment variable holding the token.
+	defaultTokenEnv = "YNAB_TOKEN"
+
+	// keyringService identifies the token in the OS secret store.
+	keyringService = "ynab-amazon-import"
+)
+
+// tokenFlags registers the YNAB token source flags.
+func (o *options) tokenFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.tokenFile, "token_file", "", "File containing the YNAB personal access token, readable only by you")
+	fs.StringVar(&o.tokenCommand, "token_command", "", "Shell command that prints the YNAB personal access token, e.g. for a password manager")
+	fs.StringVar(&o.tokenKeyring, "token_keyring", "", "Name of the YNAB personal access token in the OS secret store")
+	fs.StringVar(&o.tokenEnv, "token_env", defaultTokenEnv, "Environment variable holding the YNAB personal access token")
+	fs.StringVar(&o.token, "token", "", "YNAB personal access token; prefer the other token sources, as this exposes it in shell history and process listings")
+	fs.StringVar(&o.ynabURL, "ynab_url", "", "Optional base URL of the YNAB API, e.g. for a local stand-in server")
+}
+
+// resolveToken sets the token from the first configured source: the token
+// file, command or secret store, then the environment, and only then the
+// token flag.
+func (o *options) resolveToken() error {
+	token, source, err := o.readToken()
+	if err != nil {
+		return err
+	}
+	switch {
+	case token != "":
+		if o.token != "" {
+			log.Printf("ignoring -token in favor of the token from %s", source)
+		}
+		o.token = token
+	case o.token != "":
+		log.Printf("warning: -token exposes the token in shell history and process listings; use -token_file, -token_command, -token_keyring or $%s instead", defaultTokenEnv)
+	default:
+		return fmt.Errorf("no YNAB token: set $%s or use -token_file, -token_command or -token_keyring", o.tokenEnv)
+	}
+	return nil
+}
+
+// readToken returns the token from the first configured source other than the
+// token flag, and a description of the source.
+func (o *options) readToken() (token, source string, err error) {
+	switch {
+	case o.tokenFile != "":
+		token, err = readTokenFile(o.tokenFile)
+		source = fmt.Sprintf("file %q", o.tokenFile)
+	case o.tokenCommand != "":
+		token, err = runTokenCommand(o.tokenCommand)
+		source = fmt.Sprintf("command %q", o.tokenCommand)
+	case o.tokenKeyring != "":
+		token, err = keyring.Get(keyringService, o.tokenKeyring)
+		if err != nil {
+			err = fmt.Errorf("keyring.Get(%q): %w", o.tokenKeyring, err)
+		}
+		source = fmt.Sprintf("secret store entry %q", o.tokenKeyring)
+	case o.tokenEnv != "":
+		token, source = os.Getenv(o.tokenEnv), "$"+o.tokenEnv
+	}
+	if err != nil {
+		return "", "", err
+	}
+	// An unset environment variable falls through to the token flag, but the
+	// other sources were asked for explicitly.
+	token = strings.TrimSpace(token)
+	if token == "" && source != "" && !strings.HasPrefix(source, "$") {
+		return "", "", fmt.Errorf("empty token from %s", source)
+	}
+	return token, source, nil
+}
+
+// readTokenFile reads a token file, refusing files that other users can
+// read.
+func readTokenFile(name string) (string, error) {
+	fi, err := os.Stat(name)
+	if err != nil {
+		return "", fmt.Errorf("os.Stat(%q): %w", name, err)
+	}
+	// Windows doesn't have Unix permissions.
+	if runtime.GOOS != "windows" && fi.Mode().Perm()&0o077 != 0 {
+		return "", fmt.Errorf("token file %q is accessible by other users (mode %s); run chmod 600 %q", name, fi.Mode().Perm(), name)
+	}
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return "", fmt.Errorf("os.ReadFile(%q): %w", name, err)
+	}
+	return string(b), nil
+}
+
+// runTokenCommand runs a shell command and returns its output.
+func runTokenCommand(command string) (string, error) {
+	cmd := exec.Command("sh", "-c", command)
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	}
+	var stdout bytes.Buffer
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, &stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("token command %q: %w", command, err)
+	}
+	return stdout.String(), nil
+}
+
+// storeToken reads a token from stdin and saves it in the OS secret store.
+func storeToken(name string) error {
+	fmt.Fprintf(os.Stderr, "YNAB personal access token for %q: ", name)
+	token, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && token == "" {
+		return fmt.Errorf("failed to read token: %w", err)
+	}
+	if token = strings.TrimSpace(token); token == "" {
+		return fmt.Errorf("empty token")
+	}
+	if err := keyring.Set(keyringService, name, token); err != nil {
+		return fmt.Errorf("keyring.Set(%q): %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+// Package txn turns orders into YNAB transactions, or into tool-neutral
+// entries for other budgeting and accounting tools. Categories, accounts and
+// transaction fields come from rules, memos and payees from templates, and
+// payees may be mapped to existing YNAB payees by a payee policy.
+package txn
+
+import (
+	"unicode/utf8"
+
+	"github.com/dbinit/ynab-amazon-import/models"
+	"github.com/dbinit/ynab-amazon-import/orders"
+	"github.com/go-openapi/strfmt"
+)
+
+// Memos of the split lines for the net shipping charge or promotion of an
+// order and for the part paid by gift card, and of collapsed grocery orders
+// whose memo template renders nothing.
+const (
+	ShippingMemo  = "Shipping Charge"
+	PromotionMemo = "Total Promotions"
+	GiftCardMemo  = "Gift Card"
+	GroceryMemo   = "Groceries"
+)
+
+// Builder builds YNAB transactions from orders. The zero value builds
+// uncleared, unapproved transactions with the default templates.
+type Builder struct {
+	// AccountID is the account of the transactions that no rule routes to
+	// another account.
+	AccountID *strfmt.UUID
+
+	// Rules assign categories, accounts and transaction fields. Rules naming
+	// categories must be resolved with ResolveCategories, and rules routing
+	// orders to YNAB accounts with ResolveAccounts.
+	Rules []*Rule
+
+	// Templates render memos and payees. Nil uses the default templates.
+	Templates *Templates
+
+	// Payees maps payees to existing YNAB payees. Nil keeps the payee names.
+	Payees *PayeePolicy
+
+	// Groceries, if set, categorizes grocery orders as a whole, building a
+	// single transaction for each rather than splitting it by item. Only its
+	// category and account are used, and a category name must be resolved
+	// with ResolveCategories.
+	Groceries *Rule
+
+	// GiftCardAccount is the plain-text accounting account that entries post
+	// the part of an order paid by gift card from, e.g. an asset account
+	// holding the gift card balance. Empty posts it to the expense account.
+	GiftCardAccount string
+
+	// Default transaction fields, which rules may override.
+	Cleared   string
+	Approved  bool
+	FlagColor string
+}
+
+// templates returns the builder's templates, parsing the defaults if needed.
+func (b *Builder) templates() (*Templates, error) {
+	if b.Templates == nil {
+		ts, err := ParseTemplates("", "", "")
+		if err != nil {
+			return nil, err
+		}
+		b.Templates = ts
+	}
+	return b.Templates, nil
+}
+
+// Transactions builds new transactions from orders, in key order. Orders with
+// a single item, no fees and no gift card payment, or no items, are single
+// transactions, as are grocery orders if Groceries is set. Other orders are
+// split by item, leaving out $0 items, with a split line for any net shipping
+// charge or promotion and for each fee. The transactions are for the amount
+// charged to the account, so the part of an order paid by gift card is an
+// uncategorized inflow split line, to be categorized as the gift card's
+// spending. Orders with nothing charged to the account are skipped. Errors
+// are template errors.
+func (b *Builder) Transactions(odm map[string]*orders.Order) ([]*models.SaveTransaction, error) {
+	ts, err := b.templates()
+	if err != nil {
+		return nil, err
+	}
+	var transactions []*models.SaveTransaction
+	for _, k := range orders.SortedKeys(odm) {
+		od := odm[k]
+		if od.CardCharged() == 0 {
+			// Skip $0 orders and orders paid by gift card alone.
+			continue
+		}
+		t := &models.SaveTransaction{
+			AccountID: route(b.Rules, od, b.AccountID),
+			Amount:    ptrOf(od.CardCharged()),
+			Date:      ptrOf(od.ShipmentDate),
+			SaveTransactionWithOptionalFields: models.SaveTransactionWithOptionalFields{
+				Cleared:   b.Cleared,
+				Approved:  b.Approved,
+				FlagColor: ptrOf(b.FlagColor),
+			},
+		}
+		t.ImportID = ImportID(od)
+		applyFields(b.Rules, od, &t.SaveTransactionWithOptionalFields)
+		transactions = append(transactions, t)
+		if b.collapse(od) {
+			// Collapsed grocery order.
+			t.CategoryID = b.Groceries.CategoryID
+			d := groceryMemoData(od)
+			if t.Memo, err = render(ts.memo, d, MemoLimit); err != nil {
+				return nil, err
+			}
+			if t.Memo == "" {
+				t.Memo = GroceryMemo
+			}
+			if t.PayeeID, t.PayeeName, err = b.payee(ts, d, nil); err != nil {
+				return nil, err
+			}
+			if g := od.GiftCardTotal(); g != 0 {
+				// Split off the part paid by gift card.
+				t.Subtransactions = []*models.SaveSubTransaction{
+					{Amount: ptrOf(od.TotalCharged), CategoryID: t.CategoryID, Memo: t.Memo},
+					b.giftCardSplit(od),
+				}
+				t.CategoryID = ""
+			}
+			continue
+		}
+		if single(od) {
+			// Missing or single item.
+			var id *orders.Item
+			if len(od.Items) == 1 {
+				id = od.Items[0]
+				t.CategoryID = categorize(b.Rules, od, id)
+			}
+			d := NewMemoData(od, id)
+			if t.Memo, err = render(ts.memo, d, MemoLimit); err != nil {
+				return nil, err
+			}
+			if t.PayeeID, t.PayeeName, err = b.payee(ts, d, id); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		// Apply any promotional amounts to shipping charges.
+		defaultID, defaultName := b.Payees.Resolve(od.Retailer.Payee, false)
+		if n := od.ShippingCharge + od.TotalPromotions; n < 0 {
+			// Create a subtransaction for the remaining shipping charge.
+			t.Subtransactions = append(t.Subtransactions, &models.SaveSubTransaction{
+				Amount:    &n,
+				Memo:      truncate(ShippingMemo, MemoLimit),
+				PayeeID:   defaultID,
+				PayeeName: defaultName,
+			})
+		} else if n > 0 {
+			// Create a subtransaction for the remaining promo total.
+			t.Subtransactions = append(t.Subtransactions, &models.SaveSubTransaction{
+				Amount:    &n,
+				Memo:      truncate(PromotionMemo, MemoLimit),
+				PayeeID:   defaultID,
+				PayeeName: defaultName,
+			})
+		}
+		// Create a subtransaction for each tip and fee.
+		for _, f := range od.Fees {
+			t.Subtransactions = append(t.Subtransactions, &models.SaveSubTransaction{
+				Amount:    ptrOf(f.Amount),
+				Memo:      truncate(f.Memo, MemoLimit),
+				PayeeID:   defaultID,
+				PayeeName: defaultName,
+			})
+		}
+		if od.GiftCardTotal() != 0 {
+			t.Subtransactions = append(t.Subtransactions, b.giftCardSplit(od))
+		}
+		// The transaction memo has no item fields for split transactions.
+		if t.Memo, err = render(ts.memo, NewMemoData(od, nil), MemoLimit); err != nil {
+			return nil, err
+		}
+		// Create subtransactions for each of the order items. YNAB rejects
+		// $0 subtransactions, so free items are left out.
+		var multiPayee bool
+		for _, id := range od.Items {
+			if id.Total == 0 {
+				continue
+			}
+			d := NewMemoData(od, id)
+			memo, err := render(ts.splitMemo, d, MemoLimit)
+			if err != nil {
+				return nil, err
+			}
+			payeeID, payeeName, err := b.payee(ts, d, id)
+			if err != nil {
+				return nil, err
+			}
+			t.Subtransactions = append(t.Subtransactions, &models.SaveSubTransaction{
+				Amount:     ptrOf(id.Total),
+				CategoryID: categorize(b.Rules, od, id),
+				Memo:       memo,
+				PayeeID:    payeeID,
+				PayeeName:  payeeName,
+			})
+			if multiPayee || (payeeID == t.PayeeID && payeeName == t.PayeeName) {
+				continue
+			}
+			// If all items have the same payee, propagate it to the transaction.
+			if multiPayee = t.PayeeID != "" || t.PayeeName != ""; multiPayee {
+				t.PayeeID, t.PayeeName = "", ""
+			} else {
+				t.PayeeID, t.PayeeName = payeeID, payeeName
+			}
+		}
+	}
+	return transactions, nil
+}
+
+// collapse reports whether an order is a grocery order to build as a single
+// transaction.
+func (b *Builder) collapse(od *orders.Order) bool {
+	return od.Grocery && b.Groceries != nil
+}
+
+// single reports whether an order is a single transaction rather than a
+// split: it has no items, or one item, no fees and no gift card payment.
+func single(od *orders.Order) bool {
+	return len(od.Items) == 0 || len(od.Items) == 1 && len(od.Fees) == 0 && od.GiftCardTotal() == 0
+}
+
+// giftCardSplit returns the inflow split line for the part of an order paid
+// by gift card.
+func (b *Builder) giftCardSplit(od *orders.Order) *models.SaveSubTransaction {
+	payeeID, payeeName := b.Payees.Resolve(od.Retailer.Payee, false)
+	return &models.SaveSubTransaction{
+		Amount:    ptrOf(-od.GiftCardTotal()),
+		Memo:      truncate(GiftCardMemo, MemoLimit),
+		PayeeID:   payeeID,
+		PayeeName: payeeName,
+	}
+}
+
+// groceryMemoData returns template data for a collapsed grocery order, with
+// the seller of its items if they share one.
+func groceryMemoData(od *orders.Order) *MemoData {
+	d := NewMemoData(od, nil)
+	for i, id := range od.Items {
+		if i > 0 && id.Seller != d.Seller {
+			d.Seller = ""
+			break
+		}
+		d.Seller = id.Seller
+	}
+	return d
+}
+
+// payee renders the payee template and maps the result to an existing payee.
+// Unknown sellers fall back to the policy's fallback payee, except for the
+// balancing item of an order.
+func (b *Builder) payee(ts *Templates, d *MemoData, id *orders.Item) (strfmt.UUID, string, error) {
+	name, err := render(ts.payee, d, PayeeLimit)
+	if err != nil {
+		return "", "", err
+	}
+	payeeID, payeeName := b.Payees.Resolve(name, id != nil && id.Seller != orders.MissingSeller)
+	return payeeID, payeeName, nil
+}
+
+// ImportID returns the import ID for an order shipment, prefixed by the
+// retailer's import prefix, e.g. "AMZ:112-1234567-1234567:2023-01-02". YNAB
+// skips transactions with an import ID it has already seen, so reposting a
+// batch can't duplicate them.
+func ImportID(od *orders.Order) string {
+	return truncate(od.Retailer.ImportPrefix+od.ID+":"+od.ShipmentDate.String(), 36)
+}
+
+// ptrOf returns a pointer to a value of any type.
+func ptrOf[T any](v T) *T { return &v }
+
+// truncate as string to a maximum length.
+func truncate(s string, l int) string {
+	if l <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) < l {
+		return s
+	}
+	return string([]rune(s)[:l])
+}
@@ -0,0 +1,141 @@
+package txn
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dbinit/ynab-amazon-import/orders"
+	"github.com/go-openapi/strfmt"
+)
+
+// testOrder returns an order shipped a few days ago with the amounts and
+// items.
+func testOrder(id string, shipping, total int64, items ...int64) *orders.Order {
+	od := &orders.Order{
+		ID:             id,
+		ShipmentDate:   strfmt.Date(time.Now().AddDate(0, 0, -3)),
+		Retailer:       orders.Retailer{Payee: "Amazon", ImportPrefix: "AMZ:"},
+		ShippingCharge: shipping,
+		TotalCharged:   total,
+	}
+	for i, n := range items {
+		od.Items = append(od.Items, &orders.Item{Title: fmt.Sprintf("Item %d", i+1), Seller: "Seller", Quantity: 1, Total: n})
+	}
+	return od
+}
+
+// paid records an order's payments by card and gift card.
+func paid(od *orders.Order, card, giftCard int64) *orders.Order {
+	if card != 0 {
+		od.Payments = append(od.Payments, &orders.Payment{Instrument: "Visa ending in 1234", Amount: card})
+	}
+	if giftCard != 0 {
+		od.Payments = append(od.Payments, &orders.Payment{Instrument: "Gift Card", GiftCard: true, Amount: giftCard})
+	}
+	return od
+}
+
+func TestTransactions(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		od   *orders.Order
+		// want is the transaction amount and its split amounts, or "" if the
+		// order is skipped.
+		want string
+	}{
+		{"card only", paid(testOrder("111-0000000-0000001", 0, -10000, -10000), -10000, 0), "-10000 []"},
+		{"no payments", testOrder("111-0000000-0000001", 0, -10000, -10000), "-10000 []"},
+		// A single item order with a gift card payment is split so the gift
+		// card part can be categorized. The gift card split follows any
+		// shipping split.
+		{"single item", paid(testOrder("111-0000000-0000001", 0, -10000, -10000), -6000, -4000), "-6000 [4000 -10000]"},
+		{"split", paid(testOrder("111-0000000-0000001", -1000, -9000, -5000, -3000), -7000, -2000), "-7000 [-1000 2000 -5000 -3000]"},
+		// Free items aren't split lines, as YNAB rejects $0 splits.
+		{"free item", paid(testOrder("111-0000000-0000001", -1000, -6000, -5000, 0), -6000, 0), "-6000 [-1000 -5000]"},
+		{"gift card only", paid(testOrder("111-0000000-0000001", 0, -5000, -5000), 0, -5000), ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &Builder{AccountID: ptrOf(strfmt.UUID("22222222-2222-2222-2222-222222222222"))}
+			txns, err := b.Transactions(map[string]*orders.Order{tc.od.Key(): tc.od})
+			if err != nil {
+				t.Fatalf("Transactions() = %v", err)
+			}
+			var got string
+			if len(txns) > 1 {
+				t.Fatalf("Transactions() returned %d transactions, want at most 1", len(txns))
+			}
+			if len(txns) == 1 {
+				tr := txns[0]
+				var splits []int64
+				for _, st := range tr.Subtransactions {
+					splits = append(splits, *st.Amount)
+				}
+				got = fmt.Sprintf("%d %v", *tr.Amount, splits)
+				if *tr.Amount != tc.od.CardCharged() {
+					t.Errorf("amount %d, want CardCharged() %d", *tr.Amount, tc.od.CardCharged())
+				}
+				for _, st := range tr.Subtransactions {
+					if *st.Amount > 0 && (st.Memo != GiftCardMemo || st.CategoryID != "") {
+						t.Errorf("gift card split = %+v, want an uncategorized %q split", st, GiftCardMemo)
+					}
+				}
+			}
+			if got != tc.want {
+				t.Errorf("Transactions() = %q, want %q", got, tc.want)
+			}
+			// The splits add up to the card charge.
+			if err := Check(txns, time.Now()); err != nil {
+				t.Errorf("Check() = %v", err)
+			}
+		})
+	}
+}
+
+func TestEntriesGiftCard(t *testing.T) {
+	for _, tc := range []struct {
+		name, giftCardAccount, want string
+	}{
+		{
+			// Expenses keep the full item totals, paid partly from the gift
+			// card account.
+			name:            "gift card account",
+			giftCardAccount: "Assets:Amazon Gift Card",
+			want:            "[Expenses:Amazon 1000 Shipping Charge Expenses:Amazon 5000 Item 1 Expenses:Amazon 3000 Item 2 Assets:Amazon Gift Card -2000 Gift Card]",
+		},
+		{
+			name: "netted out of expenses",
+			want: "[Expenses:Amazon 1000 Shipping Charge Expenses:Amazon 5000 Item 1 Expenses:Amazon 3000 Item 2 Expenses:Amazon -2000 Gift Card]",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			od := paid(testOrder("111-0000000-0000001", -1000, -9000, -5000, -3000), -7000, -2000)
+			b := &Builder{GiftCardAccount: tc.giftCardAccount}
+			entries, err := b.Entries(map[string]*orders.Order{od.Key(): od}, "Expenses:Amazon")
+			if err != nil {
+				t.Fatalf("Entries() = %v", err)
+			}
+			if len(entries) != 1 {
+				t.Fatalf("Entries() returned %d entries, want 1", len(entries))
+			}
+			en := entries[0]
+			if en.Total != -7000 {
+				t.Errorf("Total = %d, want -7000", en.Total)
+			}
+			var postings []string
+			for _, p := range en.Postings {
+				postings = append(postings, fmt.Sprintf("%s %d %s", p.Account, p.Amount, p.Memo))
+			}
+			if got := fmt.Sprint(postings); got != tc.want {
+				t.Errorf("postings = %s, want %s", got, tc.want)
+			}
+
+			// Orders paid by gift card alone aren't charged to the paying
+			// account.
+			od = paid(testOrder("111-0000000-0000002", 0, -5000, -5000), 0, -5000)
+			if entries, err := b.Entries(map[string]*orders.Order{od.Key(): od}, "Expenses:Amazon"); err != nil || len(entries) != 0 {
+				t.Errorf("Entries() = %d entries, %v; want none", len(entries), err)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package txn
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dbinit/ynab-amazon-import/models"
+	"github.com/dbinit/ynab-amazon-import/orders"
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/strfmt"
+)
+
+// MaxTransactionAge is the age in years beyond which YNAB rejects dates. It
+// rejects future dates too.
+const MaxTransactionAge = 5
+
+// CheckError lists the problems found in transactions before posting, each
+// prefixed by the import ID of its transaction.
+type CheckError struct {
+	// Transactions is the number of transactions with problems.
+	Transactions int
+	Problems     []string
+}
+
+func (e *CheckError) Error() string {
+	return fmt.Sprintf("%d transactions failed checks:\n\t%s", e.Transactions, strings.Join(e.Problems, "\n\t"))
+}
+
+// Check checks transactions before posting: required fields and YNAB field
+// limits, split sums, signs, unique import IDs and dates. It returns a
+// *CheckError explaining every problem by import ID.
+func Check(txns []*models.SaveTransaction, now time.Time) error {
+	e := &CheckError{}
+	seen := make(map[string]bool)
+	for i, t := range txns {
+		name := t.ImportID
+		if name == "" {
+			name = fmt.Sprintf("transaction %d", i+1)
+		}
+		problems := checkTransaction(t, now)
+		if t.ImportID != "" {
+			if seen[t.ImportID] {
+				problems = append(problems, "import ID is used by more than one transaction, so YNAB would skip all but the first")
+			}
+			seen[t.ImportID] = true
+		}
+		for _, p := range problems {
+			e.Problems = append(e.Problems, name+": "+p)
+		}
+		if len(problems) > 0 {
+			e.Transactions++
+		}
+	}
+	if len(e.Problems) > 0 {
+		return e
+	}
+	return nil
+}
+
+// checkTransaction returns the problems with a transaction.
+func checkTransaction(t *models.SaveTransaction, now time.Time) []string {
+	problems := validationErrors(t.Validate(strfmt.Default))
+	if t.Amount == nil || t.Date == nil {
+		return problems
+	}
+
+	// Order charges are outflows.
+	if *t.Amount >= 0 {
+		problems = append(problems, fmt.Sprintf("amount %s is not an outflow", orders.FormatMoney(*t.Amount)))
+	}
+	if t.PayeeID != "" && t.PayeeName != "" {
+		problems = append(problems, fmt.Sprintf("both payee ID %s and payee name %q are set", t.PayeeID, t.PayeeName))
+	}
+
+	// Compare calendar days, whatever the time and zone of the date.
+	y, m, day := time.Time(*t.Date).Date()
+	d := time.Date(y, m, day, 0, 0, 0, 0, time.UTC)
+	today := now.UTC().Truncate(24 * time.Hour)
+	// Allow a day for time zones ahead of UTC.
+	if d.After(today.AddDate(0, 0, 1)) {
+		problems = append(problems, fmt.Sprintf("date %s is in the future", t.Date))
+	}
+	if d.Before(today.AddDate(-MaxTransactionAge, 0, 0)) {
+		problems = append(problems, fmt.Sprintf("date %s is more than %d years ago", t.Date, MaxTransactionAge))
+	}
+
+	if len(t.Subtransactions) == 0 {
+		return problems
+	}
+	if t.CategoryID != "" {
+		problems = append(problems, "split transaction has a category, which YNAB would ignore")
+	}
+	var sum int64
+	var parts []string
+	for i, st := range t.Subtransactions {
+		if st == nil || st.Amount == nil {
+			problems = append(problems, fmt.Sprintf("subtransaction %d has no amount", i+1))
+			continue
+		}
+		sum += *st.Amount
+		parts = append(parts, fmt.Sprintf("%s %q", orders.FormatMoney(*st.Amount), st.Memo))
+		if *st.Amount == 0 {
+			problems = append(problems, fmt.Sprintf("subtransaction %d (%q) has a zero amount", i+1, st.Memo))
+		}
+		if st.PayeeID != "" && st.PayeeName != "" {
+			problems = append(problems, fmt.Sprintf("subtransaction %d has both payee ID %s and payee name %q", i+1, st.PayeeID, st.PayeeName))
+		}
+	}
+	if sum != *t.Amount {
+		problems = append(problems, fmt.Sprintf("subtransactions sum to %s but the amount is %s, a difference of %s: %s",
+			orders.FormatMoney(sum), orders.FormatMoney(*t.Amount), orders.FormatMoney(*t.Amount-sum), strings.Join(parts, " + ")))
+	}
+	return problems
+}
+
+// validationErrors flattens the errors of a generated model's Validate.
+func validationErrors(err error) []string {
+	var composite *errors.CompositeError
+	if !stderrors.As(err, &composite) {
+		if err != nil {
+			return []string{err.Error()}
+		}
+		return nil
+	}
+	var msgs []string
+	for _, e := range composite.Errors {
+		msgs = append(msgs, validationErrors(e)...)
+	}
+	return msgs
+}
@@ -0,0 +1,123 @@
+package txn
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dbinit/ynab-amazon-import/models"
+	"github.com/go-openapi/strfmt"
+)
+
+func TestCheck(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	// checked returns a valid split transaction changed by edit.
+	checked := func(edit func(t *models.SaveTransaction)) *models.SaveTransaction {
+		t := &models.SaveTransaction{
+			AccountID: ptrOf(strfmt.UUID("22222222-2222-2222-2222-222222222222")),
+			Amount:    ptrOf(int64(-5000)),
+			Date:      ptrOf(strfmt.Date(now.AddDate(0, 0, -3))),
+		}
+		t.ImportID = "AMZ:111-0000000-0000001:2024-06-12"
+		t.Subtransactions = []*models.SaveSubTransaction{
+			{Amount: ptrOf(int64(-2000)), Memo: "Item 1"},
+			{Amount: ptrOf(int64(-3000)), Memo: "Item 2"},
+		}
+		if edit != nil {
+			edit(t)
+		}
+		return t
+	}
+	for _, tc := range []struct {
+		name string
+		txns []*models.SaveTransaction
+		// want has a substring of each problem, in order.
+		want []string
+	}{
+		{"valid", []*models.SaveTransaction{checked(nil)}, nil},
+		{
+			"split sum",
+			[]*models.SaveTransaction{checked(func(t *models.SaveTransaction) { *t.Subtransactions[1].Amount = -2500 })},
+			[]string{`subtransactions sum to -$4.50 but the amount is -$5.00, a difference of -$0.50: -$2.00 "Item 1" + -$2.50 "Item 2"`},
+		},
+		{
+			"zero split",
+			[]*models.SaveTransaction{checked(func(t *models.SaveTransaction) {
+				t.Subtransactions = append(t.Subtransactions, &models.SaveSubTransaction{Amount: ptrOf(int64(0)), Memo: "Free"})
+			})},
+			[]string{`subtransaction 3 ("Free") has a zero amount`},
+		},
+		{
+			"split category",
+			[]*models.SaveTransaction{checked(func(t *models.SaveTransaction) { t.CategoryID = "66666666-6666-6666-6666-666666666666" })},
+			[]string{"split transaction has a category"},
+		},
+		{
+			"inflow",
+			[]*models.SaveTransaction{checked(func(t *models.SaveTransaction) { t.Amount, t.Subtransactions = ptrOf(int64(1000)), nil })},
+			[]string{"amount $1.00 is not an outflow"},
+		},
+		{
+			"limits",
+			[]*models.SaveTransaction{checked(func(t *models.SaveTransaction) {
+				t.ImportID += ":" + strings.Repeat("x", 10)
+				t.Memo = strings.Repeat("m", 201)
+				t.PayeeName = strings.Repeat("p", 51)
+			})},
+			[]string{"import_id", "memo", "payee_name"},
+		},
+		{
+			"split limits",
+			[]*models.SaveTransaction{checked(func(t *models.SaveTransaction) { t.Subtransactions[0].Memo = strings.Repeat("m", 201) })},
+			[]string{"memo"},
+		},
+		{
+			"payee ID and name",
+			[]*models.SaveTransaction{checked(func(t *models.SaveTransaction) {
+				t.PayeeID, t.PayeeName = "77777777-7777-7777-7777-777777777777", "Amazon"
+			})},
+			[]string{`both payee ID 77777777-7777-7777-7777-777777777777 and payee name "Amazon" are set`},
+		},
+		{
+			// A day ahead is allowed for time zones ahead of UTC.
+			"tomorrow",
+			[]*models.SaveTransaction{checked(func(t *models.SaveTransaction) { t.Date = ptrOf(strfmt.Date(now.AddDate(0, 0, 1))) })},
+			nil,
+		},
+		{
+			"future",
+			[]*models.SaveTransaction{checked(func(t *models.SaveTransaction) { t.Date = ptrOf(strfmt.Date(now.AddDate(0, 0, 2))) })},
+			[]string{"date 2024-06-17 is in the future"},
+		},
+		{
+			"too old",
+			[]*models.SaveTransaction{checked(func(t *models.SaveTransaction) { t.Date = ptrOf(strfmt.Date(now.AddDate(-MaxTransactionAge, 0, -1))) })},
+			[]string{"date 2019-06-14 is more than 5 years ago"},
+		},
+		{
+			"duplicate import IDs",
+			[]*models.SaveTransaction{checked(nil), checked(nil)},
+			[]string{"import ID is used by more than one transaction"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Check(tc.txns, now)
+			var got []string
+			if err != nil {
+				ce, ok := err.(*CheckError)
+				if !ok {
+					t.Fatalf("Check() = %v, want a *CheckError", err)
+				}
+				got = ce.Problems
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("Check() problems =\n\t%s\nwant %d problems", strings.Join(got, "\n\t"), len(tc.want))
+			}
+			for i, p := range got {
+				if !strings.HasPrefix(p, "AMZ:") || !strings.Contains(p, tc.want[i]) {
+					t.Errorf("Check() problem %q, want it by import ID and containing %q", p, tc.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package txn
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dbinit/ynab-amazon-import/models"
+	"github.com/dbinit/ynab-amazon-import/orders"
+)
+
+// Entry is a tool-neutral transaction for an order shipment, for plain-text
+// accounting formats and budgeting tools other than YNAB.
+type Entry struct {
+	OrderID  string
+	ImportID string
+	Date     time.Time
+	Cleared  bool
+	Payee    string
+	Memo     string
+
+	// Total is the amount charged, and the postings balance it.
+	Total    int64
+	Postings []*Posting
+}
+
+// Posting is an expense line of an entry.
+type Posting struct {
+	// Account is the plain-text accounting account, and Category the
+	// category name, assigned by rules.
+	Account  string
+	Category string
+	Amount   int64
+	Memo     string
+}
+
+// Entries builds entries from orders, in key order. Items are posted to the
+// accounts assigned by rules, or the expense account, along with any net
+// shipping charge or promotion and any fees. Grocery orders are posted as a
+// whole if Groceries is set. As for transactions, the total is the amount
+// charged to the paying account, so any part paid by gift card is posted from
+// GiftCardAccount, or back to the expense account if it's empty. Entries that
+// don't balance are reported by a *CheckError.
+func (b *Builder) Entries(odm map[string]*orders.Order, expense string) ([]*Entry, error) {
+	ts, err := b.templates()
+	if err != nil {
+		return nil, err
+	}
+	var entries []*Entry
+	e := &CheckError{}
+	for _, k := range orders.SortedKeys(odm) {
+		od := odm[k]
+		if od.CardCharged() == 0 {
+			// Skip $0 orders and orders paid by gift card alone.
+			continue
+		}
+		fields := models.SaveTransactionWithOptionalFields{Cleared: b.Cleared}
+		applyFields(b.Rules, od, &fields)
+		en := &Entry{
+			OrderID:  od.ID,
+			ImportID: ImportID(od),
+			Date:     time.Time(od.ShipmentDate),
+			Cleared:  fields.Cleared != models.SaveTransactionWithOptionalFieldsClearedUncleared,
+			Total:    od.CardCharged(),
+		}
+		entries = append(entries, en)
+
+		if b.collapse(od) {
+			// Collapsed grocery order.
+			d := groceryMemoData(od)
+			if en.Memo, err = render(ts.memo, d, MemoLimit); err != nil {
+				return nil, err
+			}
+			if en.Memo == "" {
+				en.Memo = GroceryMemo
+			}
+			if en.Payee, err = b.payeeName(ts, d, nil); err != nil {
+				return nil, err
+			}
+			p := &Posting{Account: b.Groceries.Account, Category: b.Groceries.CategoryName, Amount: -od.TotalCharged}
+			if p.Account == "" {
+				p.Account = expense
+			}
+			en.Postings = append(en.Postings, p)
+		} else if single(od) {
+			// Missing or single item.
+			var id *orders.Item
+			p := &Posting{Account: expense, Amount: -od.CardCharged()}
+			if len(od.Items) == 1 {
+				id = od.Items[0]
+				p.Account = account(b.Rules, od, id, expense)
+				p.Category = categoryName(b.Rules, od, id)
+			}
+			d := NewMemoData(od, id)
+			if en.Memo, err = render(ts.memo, d, MemoLimit); err != nil {
+				return nil, err
+			}
+			if en.Payee, err = b.payeeName(ts, d, id); err != nil {
+				return nil, err
+			}
+			en.Postings = append(en.Postings, p)
+		} else {
+			if en.Memo, err = render(ts.memo, NewMemoData(od, nil), MemoLimit); err != nil {
+				return nil, err
+			}
+			// Net promotional amounts against shipping charges, as for splits.
+			if n := od.ShippingCharge + od.TotalPromotions; n != 0 {
+				memo := ShippingMemo
+				if n > 0 {
+					memo = PromotionMemo
+				}
+				en.Postings = append(en.Postings, &Posting{Account: expense, Amount: -n, Memo: memo})
+			}
+			for _, f := range od.Fees {
+				en.Postings = append(en.Postings, &Posting{Account: expense, Amount: -f.Amount, Memo: f.Memo})
+			}
+			for _, id := range od.Items {
+				d := NewMemoData(od, id)
+				memo, err := render(ts.splitMemo, d, MemoLimit)
+				if err != nil {
+					return nil, err
+				}
+				payee, err := b.payeeName(ts, d, id)
+				if err != nil {
+					return nil, err
+				}
+				// Use the item payee if all items share it.
+				if en.Payee == "" {
+					en.Payee = payee
+				} else if en.Payee != payee {
+					en.Payee = od.Retailer.Payee
+				}
+				en.Postings = append(en.Postings, &Posting{
+					Account:  account(b.Rules, od, id, expense),
+					Category: categoryName(b.Rules, od, id),
+					Amount:   -id.Total,
+					Memo:     memo,
+				})
+			}
+		}
+		if g := od.GiftCardTotal(); g != 0 && (b.collapse(od) || !single(od)) {
+			account := b.GiftCardAccount
+			if account == "" {
+				account = expense
+			}
+			en.Postings = append(en.Postings, &Posting{Account: account, Amount: g, Memo: GiftCardMemo})
+		}
+		sum := en.Total
+		for _, p := range en.Postings {
+			sum += p.Amount
+		}
+		if sum != 0 {
+			e.Transactions++
+			e.Problems = append(e.Problems, fmt.Sprintf("%s: postings don't balance, leaving %s", ImportID(od), orders.FormatMoney(sum)))
+		}
+	}
+	if len(e.Problems) > 0 {
+		return nil, e
+	}
+	return entries, nil
+}
+
+// payeeName renders the payee template and maps the result to a payee name.
+func (b *Builder) payeeName(ts *Templates, d *MemoData, id *orders.Item) (string, error) {
+	payeeID, payeeName, err := b.payee(ts, d, id)
+	if err != nil || payeeName != "" {
+		return payeeName, err
+	}
+	return payeeID.String(), nil
+}
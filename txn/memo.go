@@ -0,0 +1,143 @@
+package txn
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/dbinit/ynab-amazon-import/orders"
+)
+
+// YNAB field length limits.
+const (
+	MemoLimit  = 200
+	PayeeLimit = 50
+)
+
+// Default templates, matching the memos and payees of earlier versions.
+const (
+	// itemTemplate describes a single item, e.g. "3x AA Batteries ($4.99 ea)".
+	itemTemplate = `{{if gt .Quantity 1}}{{.Quantity}}x {{end}}{{.Title}}{{if and (gt .Quantity 1) .UnitPrice}} ({{.UnitPrice}} ea){{end}}`
+
+	defaultMemoTemplate      = `{{if eq .Items 1}}` + itemTemplate + `{{else if eq .Items 0}}{{.OrderURL}}{{end}}`
+	defaultSplitMemoTemplate = itemTemplate
+	defaultPayeeTemplate     = `{{with .Seller}}{{.}}{{else}}{{.Retailer}}{{end}}`
+)
+
+// MemoData is the data available to memo and payee templates. For a
+// transaction with a single item, the item fields are populated.
+type MemoData struct {
+	// Retailer is the retailer's default payee, e.g. "Amazon".
+	Retailer     string
+	OrderID      string
+	ShipmentDate string
+	OrderURL     string
+	Items        int
+
+	// Purchasing details of business orders.
+	PONumber     string
+	AccountGroup string
+	Buyer        string
+	Approver     string
+
+	Title     string
+	Seller    string
+	Quantity  int64
+	UnitPrice string
+	ASIN      string
+	Category  string
+}
+
+// NewMemoData returns template data for an order and an optional item.
+func NewMemoData(od *orders.Order, id *orders.Item) *MemoData {
+	d := &MemoData{
+		Retailer:     od.Retailer.Payee,
+		OrderID:      od.ID,
+		ShipmentDate: od.ShipmentDate.String(),
+		OrderURL:     od.URL,
+		Items:        len(od.Items),
+		PONumber:     od.PONumber,
+		AccountGroup: od.AccountGroup,
+		Buyer:        od.Buyer,
+		Approver:     od.Approver,
+	}
+	if id != nil {
+		d.Title = id.Title
+		d.Seller = id.Seller
+		d.Quantity = id.Quantity
+		d.ASIN = id.ASIN
+		d.Category = id.Category
+		if id.UnitPrice != 0 {
+			d.UnitPrice = orders.FormatMoney(id.UnitPrice)
+		}
+	}
+	return d
+}
+
+// Templates holds the parsed memo and payee templates.
+type Templates struct {
+	memo      *template.Template
+	splitMemo *template.Template
+	payee     *template.Template
+}
+
+// ParseTemplates parses the memo, split memo and payee templates, which are
+// executed with MemoData. Empty templates are replaced by the defaults.
+func ParseTemplates(memo, splitMemo, payee string) (*Templates, error) {
+	ts := &Templates{}
+	for _, t := range []struct {
+		name, text, def string
+		tmpl            **template.Template
+	}{
+		{"memo", memo, defaultMemoTemplate, &ts.memo},
+		{"split memo", splitMemo, defaultSplitMemoTemplate, &ts.splitMemo},
+		{"payee", payee, defaultPayeeTemplate, &ts.payee},
+	} {
+		if t.text == "" {
+			t.text = t.def
+		}
+		tmpl, err := template.New(t.name).Option("missingkey=error").Parse(t.text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s template: %w", t.name, err)
+		}
+		*t.tmpl = tmpl
+	}
+	return ts, nil
+}
+
+// render executes a template and fits the result to limit runes. If the result
+// is too long the title is shortened first, so that text following it (such
+// as the order URL) survives, and only then is the result truncated.
+func render(t *template.Template, d *MemoData, limit int) (string, error) {
+	s, err := execute(t, d)
+	if err != nil {
+		return "", err
+	}
+	for title := []rune(d.Title); utf8.RuneCountInString(s) > limit && len(title) > 0; {
+		// Shorten the title by the excess plus room for an ellipsis.
+		n := len(title) - (utf8.RuneCountInString(s) - limit) - 1
+		if n < 0 {
+			n = 0
+		}
+		title = title[:n]
+		short := *d
+		short.Title = strings.TrimSpace(string(title)) + "…"
+		if n == 0 {
+			short.Title = ""
+		}
+		if s, err = execute(t, &short); err != nil {
+			return "", err
+		}
+	}
+	return truncate(s, limit), nil
+}
+
+// execute executes a template and returns the trimmed result.
+func execute(t *template.Template, d *MemoData) (string, error) {
+	var b strings.Builder
+	if err := t.Execute(&b, d); err != nil {
+		return "", fmt.Errorf("failed to execute %s template: %w", t.Name(), err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
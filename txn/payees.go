@@ -0,0 +1,124 @@
+package txn
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/dbinit/ynab-amazon-import/models"
+	"github.com/go-openapi/strfmt"
+)
+
+// PayeePolicy maps sellers to existing YNAB payees.
+type PayeePolicy struct {
+	// Exact maps sellers to existing YNAB payees with the same name, ignoring
+	// case.
+	Exact bool `json:"exact"`
+
+	// Rules map sellers to payees by alias or regular expression. The first
+	// matching rule wins.
+	Rules []*PayeeRule `json:"rules"`
+
+	// Fallback is the payee for sellers that match no rule and no existing
+	// payee, e.g. "Amazon". Empty keeps the seller name.
+	Fallback string `json:"fallback,omitempty"`
+
+	// Existing YNAB payee IDs by lower case name.
+	ids map[string]strfmt.UUID
+}
+
+// PayeeRule maps sellers to a payee.
+type PayeeRule struct {
+	// Payee is the name of the payee to use.
+	Payee string `json:"payee"`
+
+	// Aliases are seller names that map to the payee, ignoring case.
+	Aliases []string `json:"aliases,omitempty"`
+
+	// Regex is a regular expression matching seller names.
+	Regex string `json:"regex,omitempty"`
+
+	re *regexp.Regexp
+}
+
+// LoadPayeePolicy loads a payee policy from a JSON file. An empty name returns
+// a nil policy, which keeps payee names as they are.
+func LoadPayeePolicy(name string) (*PayeePolicy, error) {
+	if name == "" {
+		return nil, nil
+	}
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile(%q): %w", name, err)
+	}
+	pp := &PayeePolicy{}
+	if err := json.Unmarshal(b, pp); err != nil {
+		return nil, fmt.Errorf("failed to parse payee policy %q: %w", name, err)
+	}
+	for i, r := range pp.Rules {
+		if r.Payee == "" {
+			return nil, fmt.Errorf("payee rule %d in %q has no payee", i+1, name)
+		}
+		if r.Regex == "" {
+			continue
+		}
+		if r.re, err = regexp.Compile(r.Regex); err != nil {
+			return nil, fmt.Errorf("payee rule %q in %q: %w", r.Payee, name, err)
+		}
+	}
+	return pp, nil
+}
+
+// SetPayees indexes existing payees, skipping deleted and transfer payees, and
+// returns the number indexed.
+func (pp *PayeePolicy) SetPayees(ps []*models.Payee) int {
+	pp.ids = make(map[string]strfmt.UUID)
+	for _, p := range ps {
+		if p == nil || p.ID == nil || p.Name == nil || p.TransferAccountID != "" || (p.Deleted != nil && *p.Deleted) {
+			continue
+		}
+		pp.ids[strings.ToLower(*p.Name)] = *p.ID
+	}
+	return len(pp.ids)
+}
+
+// Resolve maps a payee name to an existing payee ID or a payee name. Only one
+// of the results is set. The fallback payee is only used if fallback is true.
+// A nil policy keeps the name.
+func (pp *PayeePolicy) Resolve(name string, fallback bool) (strfmt.UUID, string) {
+	if pp == nil {
+		return "", name
+	}
+	target := name
+	if r := pp.match(name); r != nil {
+		target = r.Payee
+	} else if _, ok := pp.ids[strings.ToLower(name)]; ok {
+		if !pp.Exact {
+			// An existing payee isn't a fallback case, but without Exact it
+			// isn't mapped to its ID either.
+			return "", name
+		}
+		target = name
+	} else if fallback && pp.Fallback != "" {
+		target = pp.Fallback
+	} else {
+		// Unmapped, so leave it to YNAB to resolve the name.
+		return "", name
+	}
+	if id, ok := pp.ids[strings.ToLower(target)]; ok {
+		return id, ""
+	}
+	return "", truncate(target, PayeeLimit)
+}
+
+// match returns the first rule matching a seller name, or nil.
+func (pp *PayeePolicy) match(name string) *PayeeRule {
+	for _, r := range pp.Rules {
+		if containsFold(r.Aliases, name) || (r.re != nil && r.re.MatchString(name)) {
+			return r
+		}
+	}
+	return nil
+}
@@ -0,0 +1,369 @@
+package txn
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/dbinit/ynab-amazon-import/models"
+	"github.com/dbinit/ynab-amazon-import/orders"
+	"github.com/go-openapi/strfmt"
+)
+
+// Rule assigns a YNAB category to items matching all of its conditions, and
+// transaction fields and a YNAB account to orders matching its order
+// conditions and containing a matching item. Empty conditions match
+// everything.
+type Rule struct {
+	// Name identifies the rule in errors and logs.
+	Name string `json:"name"`
+
+	// Title, Seller and Category are regular expressions matched against the
+	// item title, seller and retailer category.
+	Title    string `json:"title,omitempty"`
+	Seller   string `json:"seller,omitempty"`
+	Category string `json:"category,omitempty"`
+
+	// ASIN matches any of the listed ASIN/ISBN values exactly.
+	ASIN []string `json:"asin,omitempty"`
+
+	// UNSPSC matches UNSPSC codes with this prefix, e.g. "5010" for all food
+	// and beverage products. Amazon Business exports these as the business
+	// category codes of the items.
+	UNSPSC string `json:"unspsc,omitempty"`
+
+	// MinQuantity matches items ordered at least this many times.
+	MinQuantity int64 `json:"min_quantity,omitempty"`
+
+	// MinAmount matches orders charged at least this amount, e.g. "100.00".
+	MinAmount string `json:"min_amount,omitempty"`
+
+	// Missing matches orders with an unexplained remainder.
+	Missing bool `json:"missing,omitempty"`
+
+	// SubscribeAndSave matches Subscribe & Save deliveries. Only the
+	// amazon-email and amazon-invoice sources recognize them, so rules for
+	// the CSV sources have to match the items' titles instead.
+	SubscribeAndSave bool `json:"subscribe_and_save,omitempty"`
+
+	// AccountGroup is a regular expression matched against the purchasing
+	// account group of business orders.
+	AccountGroup string `json:"account_group,omitempty"`
+
+	// CategoryID is the YNAB category to assign to matching items.
+	CategoryID strfmt.UUID `json:"category_id,omitempty"`
+
+	// CategoryName names the YNAB category instead of CategoryID, optionally
+	// qualified by its group, e.g. "Monthly Bills: Groceries".
+	CategoryName string `json:"category_name,omitempty"`
+
+	// Account is the plain-text accounting account to post matching items
+	// to, e.g. "Expenses:Groceries".
+	Account string `json:"account,omitempty"`
+
+	// YNABAccount names the YNAB account, or gives its ID, to import matching
+	// orders into instead of the -account account.
+	YNABAccount string `json:"ynab_account,omitempty"`
+
+	// Cleared, Approved and FlagColor override the transaction fields of
+	// matching orders.
+	Cleared   string  `json:"cleared,omitempty"`
+	Approved  *bool   `json:"approved,omitempty"`
+	FlagColor *string `json:"flag_color,omitempty"`
+
+	titleRE, sellerRE, categoryRE, accountGroupRE *regexp.Regexp
+	minAmount                                     int64
+	ynabAccountID                                 *strfmt.UUID
+}
+
+// LoadRules loads categorization rules from a JSON file. An empty name returns
+// no rules.
+func LoadRules(name string) ([]*Rule, error) {
+	if name == "" {
+		return nil, nil
+	}
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile(%q): %w", name, err)
+	}
+	var rs []*Rule
+	if err := json.Unmarshal(b, &rs); err != nil {
+		return nil, fmt.Errorf("failed to parse rules %q: %w", name, err)
+	}
+	for i, r := range rs {
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("rule %d", i+1)
+		}
+		if err := r.compile(); err != nil {
+			return nil, fmt.Errorf("invalid rule %q in %q: %w", r.Name, name, err)
+		}
+	}
+	return rs, nil
+}
+
+// compile validates the rule and compiles its regular expressions.
+func (r *Rule) compile() error {
+	if r.CategoryID != "" && !strfmt.IsUUID(r.CategoryID.String()) {
+		return fmt.Errorf("category_id %q is not a UUID", r.CategoryID)
+	}
+	if r.CategoryID != "" && r.CategoryName != "" {
+		return fmt.Errorf("category_id and category_name are mutually exclusive")
+	}
+	if err := ValidateFields(r.Cleared, r.FlagColor); err != nil {
+		return err
+	}
+	if r.MinAmount != "" {
+		n, err := orders.ParseMoney(r.MinAmount, false)
+		if err != nil {
+			return fmt.Errorf("min_amount: %w", err)
+		}
+		r.minAmount = n
+	}
+	res := []**regexp.Regexp{&r.titleRE, &r.sellerRE, &r.categoryRE, &r.accountGroupRE}
+	for i, expr := range []string{r.Title, r.Seller, r.Category, r.AccountGroup} {
+		if expr == "" {
+			continue
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return err
+		}
+		*res[i] = re
+	}
+	return nil
+}
+
+// ResolveCategories sets the category IDs of rules that name their category.
+// Names match case-insensitively, and must be qualified by the group if the
+// name is used in more than one group.
+func ResolveCategories(rs []*Rule, gs []*models.CategoryGroupWithCategories) error {
+	ids := make(map[string][]strfmt.UUID)
+	for _, g := range gs {
+		if g == nil || g.Name == nil || (g.Deleted != nil && *g.Deleted) {
+			continue
+		}
+		for _, c := range g.Categories {
+			if c == nil || c.ID == nil || c.Name == nil || (c.Deleted != nil && *c.Deleted) {
+				continue
+			}
+			name := strings.ToLower(*c.Name)
+			ids[name] = append(ids[name], *c.ID)
+			ids[strings.ToLower(*g.Name)+": "+name] = []strfmt.UUID{*c.ID}
+		}
+	}
+	for _, r := range rs {
+		if r.CategoryName == "" {
+			continue
+		}
+		switch matches := ids[strings.ToLower(r.CategoryName)]; len(matches) {
+		case 0:
+			return fmt.Errorf("rule %q: category %q not found", r.Name, r.CategoryName)
+		case 1:
+			r.CategoryID = matches[0]
+		default:
+			return fmt.Errorf("rule %q: category %q is in more than one group; qualify it as \"Group: Category\"", r.Name, r.CategoryName)
+		}
+	}
+	return nil
+}
+
+// NamesCategories reports whether any rule names its category.
+func NamesCategories(rs []*Rule) bool {
+	for _, r := range rs {
+		if r.CategoryName != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveAccounts sets the YNAB accounts of rules that route orders to one,
+// looking each up by name or ID with find.
+func ResolveAccounts(rs []*Rule, find func(account string) (*strfmt.UUID, error)) error {
+	for _, r := range rs {
+		if r.YNABAccount == "" {
+			continue
+		}
+		id, err := find(r.YNABAccount)
+		if err != nil {
+			return fmt.Errorf("rule %q: %w", r.Name, err)
+		}
+		r.ynabAccountID = id
+	}
+	return nil
+}
+
+// RoutesAccounts reports whether any rule routes orders to a YNAB account.
+func RoutesAccounts(rs []*Rule) bool {
+	for _, r := range rs {
+		if r.YNABAccount != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// matchOrder reports whether an order matches the rule's order conditions.
+func (r *Rule) matchOrder(od *orders.Order) bool {
+	if r.Missing && !od.HasMissing() {
+		return false
+	}
+	if r.SubscribeAndSave && !od.SubscribeAndSave {
+		return false
+	}
+	if r.accountGroupRE != nil && !r.accountGroupRE.MatchString(od.AccountGroup) {
+		return false
+	}
+	return r.minAmount == 0 || abs(od.TotalCharged) >= r.minAmount
+}
+
+// hasItemConditions reports whether the rule has any item conditions.
+func (r *Rule) hasItemConditions() bool {
+	return r.Title != "" || r.Seller != "" || r.Category != "" || len(r.ASIN) > 0 || r.UNSPSC != "" || r.MinQuantity > 0
+}
+
+// match reports whether an item matches all of the rule's item conditions.
+func (r *Rule) match(id *orders.Item) bool {
+	if r.titleRE != nil && !r.titleRE.MatchString(id.Title) {
+		return false
+	}
+	if r.sellerRE != nil && !r.sellerRE.MatchString(id.Seller) {
+		return false
+	}
+	if r.categoryRE != nil && !r.categoryRE.MatchString(id.Category) {
+		return false
+	}
+	if len(r.ASIN) > 0 && !containsFold(r.ASIN, id.ASIN) {
+		return false
+	}
+	if r.UNSPSC != "" && !strings.HasPrefix(id.UNSPSC, r.UNSPSC) {
+		return false
+	}
+	return id.Quantity >= r.MinQuantity
+}
+
+// categorize returns the category of the first categorizing rule matching an
+// order item.
+func categorize(rs []*Rule, od *orders.Order, id *orders.Item) strfmt.UUID {
+	for _, r := range rs {
+		if r.CategoryID != "" && r.matchOrder(od) && r.match(id) {
+			return r.CategoryID
+		}
+	}
+	return ""
+}
+
+// CategoryRule returns the first rule assigning a category or a plain-text
+// accounting account to an order item, or nil.
+func CategoryRule(rs []*Rule, od *orders.Order, id *orders.Item) *Rule {
+	for _, r := range rs {
+		if (r.CategoryID != "" || r.CategoryName != "" || r.Account != "") && r.matchOrder(od) && r.match(id) {
+			return r
+		}
+	}
+	return nil
+}
+
+// account returns the plain-text accounting account of the first rule with
+// an account matching an order item, or def.
+func account(rs []*Rule, od *orders.Order, id *orders.Item, def string) string {
+	for _, r := range rs {
+		if r.Account != "" && r.matchOrder(od) && r.match(id) {
+			return r.Account
+		}
+	}
+	return def
+}
+
+// categoryName returns the category name of the first rule naming a category
+// matching an order item.
+func categoryName(rs []*Rule, od *orders.Order, id *orders.Item) string {
+	for _, r := range rs {
+		if r.CategoryName != "" && r.matchOrder(od) && r.match(id) {
+			return r.CategoryName
+		}
+	}
+	return ""
+}
+
+// route returns the resolved YNAB account of the first rule with an account
+// matching an order and one of its items, or def.
+func route(rs []*Rule, od *orders.Order, def *strfmt.UUID) *strfmt.UUID {
+	for _, r := range rs {
+		if r.ynabAccountID == nil || !r.matchOrder(od) {
+			continue
+		}
+		if r.hasItemConditions() && !anyItem(od, r.match) {
+			continue
+		}
+		return r.ynabAccountID
+	}
+	return def
+}
+
+// applyFields sets the cleared, approved and flag fields of an order's
+// transaction from the first matching rule that sets each field.
+func applyFields(rs []*Rule, od *orders.Order, t *models.SaveTransactionWithOptionalFields) {
+	var cleared, approved, flag bool
+	for _, r := range rs {
+		if !r.matchOrder(od) {
+			continue
+		}
+		if r.hasItemConditions() && !anyItem(od, r.match) {
+			continue
+		}
+		if r.Cleared != "" && !cleared {
+			t.Cleared, cleared = r.Cleared, true
+		}
+		if r.Approved != nil && !approved {
+			t.Approved, approved = *r.Approved, true
+		}
+		if r.FlagColor != nil && !flag {
+			t.FlagColor, flag = r.FlagColor, true
+		}
+	}
+	if t.FlagColor != nil && *t.FlagColor == "" {
+		// An empty flag color clears a default flag.
+		t.FlagColor = nil
+	}
+}
+
+// ValidateFields validates cleared and flag color values against the YNAB
+// enums. An empty flag color is valid, and clears a default flag.
+func ValidateFields(cleared string, flagColor *string) error {
+	t := &models.SaveTransactionWithOptionalFields{Cleared: cleared, FlagColor: flagColor}
+	if flagColor != nil && *flagColor == "" {
+		t.FlagColor = nil
+	}
+	return t.Validate(strfmt.Default)
+}
+
+// anyItem reports whether any item of an order satisfies f.
+func anyItem(od *orders.Order, f func(*orders.Item) bool) bool {
+	for _, id := range od.Items {
+		if f(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// abs returns the absolute value of an amount.
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// containsFold reports whether a list contains a string, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
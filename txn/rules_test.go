@@ -0,0 +1,57 @@
+package txn
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dbinit/ynab-amazon-import/models"
+	"github.com/dbinit/ynab-amazon-import/orders"
+)
+
+func TestApplyFields(t *testing.T) {
+	red, purple := "red", "purple"
+	rules := []*Rule{
+		{Name: "missing", Missing: true, FlagColor: &red},
+		{Name: "subscribe & save", SubscribeAndSave: true, FlagColor: &purple},
+		{Name: "large", MinAmount: "100.00", Cleared: models.SaveTransactionWithOptionalFieldsClearedUncleared, Approved: ptrOf(false)},
+	}
+	for _, r := range rules {
+		if err := r.compile(); err != nil {
+			t.Fatalf("compile(%q) = %v", r.Name, err)
+		}
+	}
+	subscribed := func(od *orders.Order) *orders.Order {
+		od.SubscribeAndSave = true
+		return od
+	}
+	// missing balances an order with a remainder item, as Merge would.
+	missing := func(od *orders.Order) *orders.Order {
+		od.Items = append(od.Items, &orders.Item{Title: od.URL, Seller: orders.MissingSeller, Total: -1000})
+		return od
+	}
+	for _, tc := range []struct {
+		name string
+		od   *orders.Order
+		// want is the flag color, cleared and approved fields.
+		want string
+	}{
+		{"no match", testOrder("111-0000000-0000001", 0, -5000, -5000), " cleared true"},
+		{"missing", missing(testOrder("111-0000000-0000001", 0, -5000, -4000)), "red cleared true"},
+		{"subscribe & save", subscribed(testOrder("111-0000000-0000001", 0, -5000, -5000)), "purple cleared true"},
+		// The first matching rule sets each field.
+		{"missing subscribe & save", missing(subscribed(testOrder("111-0000000-0000001", 0, -5000, -4000))), "red cleared true"},
+		{"large", subscribed(testOrder("111-0000000-0000001", 0, -150000, -150000)), "purple uncleared false"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := &models.SaveTransactionWithOptionalFields{Cleared: models.SaveTransactionWithOptionalFieldsClearedCleared, Approved: true}
+			applyFields(rules, tc.od, f)
+			flag := ""
+			if f.FlagColor != nil {
+				flag = *f.FlagColor
+			}
+			if got := fmt.Sprintf("%s %s %t", flag, f.Cleared, f.Approved); got != tc.want {
+				t.Errorf("applyFields() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/dbinit/ynab-amazon-import/client"
+	"github.com/dbinit/ynab-amazon-import/models"
+	"github.com/dbinit/ynab-amazon-import/ynabsync"
+	httptransport "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+)
+
+// budgetAccount finds the selected budget and account and returns the IDs.
+func budgetAccount(ctx context.Context, budget, account string, c *ynabsync.Cache) (*strfmt.UUID, *strfmt.UUID, error) {
+	b, err := c.Budget(ctx, budget)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Printf("budget %q found with ID %s", *b.Name, b.ID)
+
+	as, err := c.Accounts(ctx, b.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	a, err := ynabsync.FindAccount(b, as, account)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Printf("account %q found with ID %s", *a.Name, a.ID)
+	if a.Type == nil || (*a.Type != models.AccountTypeCreditCard && *a.Type != models.AccountTypeChecking) {
+		var typ models.AccountType
+		if a.Type != nil {
+			typ = *a.Type
+		}
+		log.Printf("warning: account %q is a %q account, not a credit card or checking account", *a.Name, typ)
+	}
+	return b.ID, a.ID, nil
+}
+
+// useYNABURL points the YNAB API client at the ynab_url flag, if it's set.
+func (o *options) useYNABURL() error {
+	if o.ynabURL == "" {
+		return nil
+	}
+	u, err := url.Parse(o.ynabURL)
+	if err != nil {
+		return fmt.Errorf("url.Parse(%q): %w", o.ynabURL, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid YNAB API URL %q", o.ynabURL)
+	}
+	client.Default = client.New(httptransport.New(u.Host, u.Path, []string{u.Scheme}), strfmt.Default)
+	return nil
+}
+
+// ynabClient returns a YNAB API client using the token, which logs waits for
+// the rate limit.
+func (o *options) ynabClient() *ynabsync.Client {
+	if o.client == nil {
+		o.client = ynabsync.New(o.token)
+		o.client.Limiter.Logf = log.Printf
+	}
+	return o.client
+}
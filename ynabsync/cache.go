@@ -0,0 +1,329 @@
+package ynabsync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dbinit/ynab-amazon-import/models"
+	"github.com/go-openapi/strfmt"
+)
+
+// Cache caches budget metadata locally. The budget list is only fetched
+// again when a budget can't be found in it, and accounts, categories and
+// payees are refreshed with the changes since the cached server knowledge, so
+// each costs at most one small request per run.
+type Cache struct {
+	// Dir is the cache directory. Empty disables the cache files, so
+	// everything is fetched in full.
+	Dir string
+
+	// Refresh ignores the cache files and fetches everything in full.
+	Refresh bool
+
+	Client *Client
+
+	// Logf logs unreadable cache files, which are ignored, if it isn't nil.
+	Logf func(format string, args ...any)
+
+	list    *BudgetList
+	budgets map[strfmt.UUID]*budgetMeta
+}
+
+// BudgetList is the cached list of budgets.
+type BudgetList struct {
+	Fetched time.Time               `json:"fetched"`
+	Budgets []*models.BudgetSummary `json:"budgets"`
+	Default *models.BudgetSummary   `json:"default_budget,omitempty"`
+
+	// fresh is set if the list was fetched in this run.
+	fresh bool
+}
+
+// budgetMeta is the cached metadata of a budget.
+type budgetMeta struct {
+	Accounts   cachedSet[*models.Account]                     `json:"accounts"`
+	Categories cachedSet[*models.CategoryGroupWithCategories] `json:"categories"`
+	Payees     cachedSet[*models.Payee]                       `json:"payees"`
+}
+
+// cachedSet is a set of entities and the server knowledge they reflect.
+type cachedSet[T any] struct {
+	ServerKnowledge int64 `json:"server_knowledge,omitempty"`
+	Items           []T   `json:"items,omitempty"`
+
+	// fresh is set if the set was refreshed in this run.
+	fresh bool
+}
+
+// BudgetList returns the budget list, fetching it if it isn't cached or if
+// fetch is true.
+func (c *Cache) BudgetList(ctx context.Context, fetch bool) (*BudgetList, error) {
+	if c.list == nil && !fetch && !c.Refresh {
+		c.list = &BudgetList{}
+		if ok, err := c.load("budgets", c.list); err != nil {
+			return nil, err
+		} else if !ok {
+			c.list = nil
+		}
+	}
+	if c.list != nil && (c.list.fresh || !fetch) {
+		return c.list, nil
+	}
+	bs, def, err := c.Client.Budgets(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	c.list = &BudgetList{Fetched: time.Now().UTC(), Budgets: bs, Default: def, fresh: true}
+	return c.list, c.save("budgets", c.list)
+}
+
+// Budget returns the selected budget. If it isn't in the cached list, the list
+// is fetched again in case the budget is new or renamed.
+func (c *Cache) Budget(ctx context.Context, budget string) (*models.BudgetSummary, error) {
+	l, err := c.BudgetList(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	b, err := c.findBudget(ctx, l, budget)
+	if err != nil && !l.fresh {
+		if l, err = c.BudgetList(ctx, true); err != nil {
+			return nil, err
+		}
+		b, err = c.findBudget(ctx, l, budget)
+	}
+	return b, err
+}
+
+// findBudget returns the selected budget from a budget list, resolving the
+// last-used budget.
+func (c *Cache) findBudget(ctx context.Context, l *BudgetList, budget string) (*models.BudgetSummary, error) {
+	if budget != LastUsedBudget {
+		return FindBudget(l.Budgets, l.Default, budget)
+	}
+	id, err := c.lastUsedBudgetID(ctx, l)
+	if err != nil {
+		return nil, err
+	}
+	return FindBudget(l.Budgets, nil, id.String())
+}
+
+// lastUsedBudgetID returns the ID of the last-used budget. The API only
+// resolves "last-used" in paths, so the budget is identified by its accounts.
+func (c *Cache) lastUsedBudgetID(ctx context.Context, l *BudgetList) (*strfmt.UUID, error) {
+	as, _, err := c.Client.Accounts(ctx, LastUsedBudget, nil)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[strfmt.UUID]bool)
+	for _, a := range as {
+		if a != nil && a.ID != nil {
+			ids[*a.ID] = true
+		}
+	}
+	find := func(bs []*models.BudgetSummary, accounts func(b *models.BudgetSummary) []*models.Account) *strfmt.UUID {
+		for _, b := range bs {
+			if b == nil || b.ID == nil {
+				continue
+			}
+			for _, a := range accounts(b) {
+				if a != nil && a.ID != nil && ids[*a.ID] {
+					return b.ID
+				}
+			}
+		}
+		return nil
+	}
+
+	// Try the cached accounts first, then fetch the budgets with their
+	// accounts.
+	id := find(l.Budgets, func(b *models.BudgetSummary) []*models.Account {
+		m, err := c.meta(b.ID)
+		if err != nil {
+			return nil
+		}
+		return m.Accounts.Items
+	})
+	if id != nil {
+		return id, nil
+	}
+	bs, _, err := c.Client.Budgets(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	if id = find(bs, func(b *models.BudgetSummary) []*models.Account { return b.Accounts }); id != nil {
+		return id, nil
+	}
+	return nil, fmt.Errorf("the last-used budget has no accounts to identify it by; select it by name or ID")
+}
+
+// meta returns the cached metadata of a budget.
+func (c *Cache) meta(budgetID *strfmt.UUID) (*budgetMeta, error) {
+	if m, ok := c.budgets[*budgetID]; ok {
+		return m, nil
+	}
+	m := &budgetMeta{}
+	if !c.Refresh {
+		if _, err := c.load(budgetID.String(), m); err != nil {
+			return nil, err
+		}
+	}
+	if c.budgets == nil {
+		c.budgets = make(map[strfmt.UUID]*budgetMeta)
+	}
+	c.budgets[*budgetID] = m
+	return m, nil
+}
+
+// Accounts returns the accounts of a budget, including closed and deleted
+// accounts.
+func (c *Cache) Accounts(ctx context.Context, budgetID *strfmt.UUID) ([]*models.Account, error) {
+	m, err := c.meta(budgetID)
+	if err != nil {
+		return nil, err
+	}
+	if err := refreshSet(ctx, c, budgetID, &m.Accounts, c.Client.Accounts, func(a *models.Account) *strfmt.UUID { return a.ID }); err != nil {
+		return nil, err
+	}
+	return m.Accounts.Items, nil
+}
+
+// Categories returns the category groups of a budget, including hidden and
+// deleted groups and categories.
+func (c *Cache) Categories(ctx context.Context, budgetID *strfmt.UUID) ([]*models.CategoryGroupWithCategories, error) {
+	m, err := c.meta(budgetID)
+	if err != nil {
+		return nil, err
+	}
+	// Changed categories come back in their groups, so merge the categories
+	// of each changed group into the cached group.
+	delta := m.Categories.ServerKnowledge > 0 && !m.Categories.fresh
+	old := make(map[strfmt.UUID][]*models.Category)
+	for _, g := range m.Categories.Items {
+		if g != nil && g.ID != nil {
+			old[*g.ID] = g.Categories
+		}
+	}
+	if err := refreshSet(ctx, c, budgetID, &m.Categories, c.Client.Categories, func(g *models.CategoryGroupWithCategories) *strfmt.UUID { return g.ID }); err != nil {
+		return nil, err
+	}
+	if !delta {
+		return m.Categories.Items, nil
+	}
+	for _, g := range m.Categories.Items {
+		if g != nil && g.ID != nil {
+			g.Categories = mergeByID(old[*g.ID], g.Categories, func(c *models.Category) *strfmt.UUID { return c.ID })
+		}
+	}
+	return m.Categories.Items, c.save(budgetID.String(), m)
+}
+
+// Payees returns the payees of a budget, including deleted payees.
+func (c *Cache) Payees(ctx context.Context, budgetID *strfmt.UUID) ([]*models.Payee, error) {
+	m, err := c.meta(budgetID)
+	if err != nil {
+		return nil, err
+	}
+	if err := refreshSet(ctx, c, budgetID, &m.Payees, c.Client.Payees, func(p *models.Payee) *strfmt.UUID { return p.ID }); err != nil {
+		return nil, err
+	}
+	return m.Payees.Items, nil
+}
+
+// refreshSet refreshes a cached set once per run with the changes since its
+// server knowledge, and saves the budget metadata.
+func refreshSet[T any](ctx context.Context, c *Cache, budgetID *strfmt.UUID, s *cachedSet[T],
+	fetch func(context.Context, string, *int64) ([]T, int64, error), id func(T) *strfmt.UUID) error {
+	if s.fresh {
+		return nil
+	}
+	var knowledge *int64
+	if s.ServerKnowledge > 0 {
+		k := s.ServerKnowledge
+		knowledge = &k
+	}
+	items, k, err := fetch(ctx, budgetID.String(), knowledge)
+	if err != nil {
+		return err
+	}
+	if knowledge == nil {
+		s.Items = items
+	} else {
+		s.Items = mergeByID(s.Items, items, id)
+	}
+	s.ServerKnowledge, s.fresh = k, true
+	return c.save(budgetID.String(), c.budgets[*budgetID])
+}
+
+// mergeByID replaces the old items with changed items of the same ID, and adds
+// new items.
+func mergeByID[T any](old, changed []T, id func(T) *strfmt.UUID) []T {
+	index := make(map[strfmt.UUID]int)
+	merged := make([]T, 0, len(old)+len(changed))
+	for _, items := range [][]T{old, changed} {
+		for _, item := range items {
+			itemID := id(item)
+			if itemID == nil {
+				continue
+			}
+			if i, ok := index[*itemID]; ok {
+				merged[i] = item
+				continue
+			}
+			index[*itemID] = len(merged)
+			merged = append(merged, item)
+		}
+	}
+	return merged
+}
+
+// load reads a cache file, reporting whether it exists. Unreadable cache
+// files are ignored.
+func (c *Cache) load(name string, v any) (bool, error) {
+	if c.Dir == "" {
+		return false, nil
+	}
+	file := filepath.Join(c.Dir, name+".json")
+	b, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("os.ReadFile(%q): %w", file, err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		if c.Logf != nil {
+			c.Logf("ignoring cache file %q: %v", file, err)
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+// save writes a cache file.
+func (c *Cache) save(name string, v any) error {
+	if c.Dir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(c.Dir, 0o700); err != nil {
+		return fmt.Errorf("os.MkdirAll(%q): %w", c.Dir, err)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("json.Marshal(): %w", err)
+	}
+	// Write and rename so concurrent runs never read a partial file.
+	file := filepath.Join(c.Dir, name+".json")
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return fmt.Errorf("os.WriteFile(%q): %w", tmp, err)
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		return fmt.Errorf("os.Rename(%q): %w", tmp, err)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package ynabsync
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dbinit/ynab-amazon-import/models"
+	"github.com/go-openapi/strfmt"
+)
+
+// Test IDs.
+const (
+	testPayee1    = "33333333-3333-3333-3333-333333333331"
+	testPayee2    = "33333333-3333-3333-3333-333333333332"
+	testPayee3    = "33333333-3333-3333-3333-333333333333"
+	testGroup1    = "44444444-4444-4444-4444-444444444441"
+	testGroup2    = "44444444-4444-4444-4444-444444444442"
+	testCategory1 = "55555555-5555-5555-5555-555555555551"
+	testCategory2 = "55555555-5555-5555-5555-555555555552"
+	testCategory3 = "55555555-5555-5555-5555-555555555553"
+)
+
+// ptrTo returns a pointer to a value of any type.
+func ptrTo[T any](v T) *T { return &v }
+
+func testPayee(id, name string) *models.Payee {
+	return &models.Payee{ID: (*strfmt.UUID)(&id), Name: &name}
+}
+
+// payeeNames describes payees as "name" or "name (deleted)".
+func payeeNames(ps []*models.Payee) []string {
+	var names []string
+	for _, p := range ps {
+		name := *p.Name
+		if p.Deleted != nil && *p.Deleted {
+			name += " (deleted)"
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestMergeByID(t *testing.T) {
+	id := func(p *models.Payee) *strfmt.UUID { return p.ID }
+	for _, tc := range []struct {
+		name         string
+		old, changed []*models.Payee
+		want         []string
+	}{
+		{"no changes", []*models.Payee{testPayee(testPayee1, "A"), testPayee(testPayee2, "B")}, nil, []string{"A", "B"}},
+		{"renamed", []*models.Payee{testPayee(testPayee1, "A"), testPayee(testPayee2, "B")}, []*models.Payee{testPayee(testPayee1, "A2")}, []string{"A2", "B"}},
+		{"added", []*models.Payee{testPayee(testPayee1, "A")}, []*models.Payee{testPayee(testPayee3, "C")}, []string{"A", "C"}},
+		{"nothing cached", nil, []*models.Payee{testPayee(testPayee2, "B"), testPayee(testPayee1, "A")}, []string{"B", "A"}},
+		{"no ID", []*models.Payee{testPayee(testPayee1, "A"), {Name: ptrTo("anonymous")}}, nil, []string{"A"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := payeeNames(mergeByID(tc.old, tc.changed, id)); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("mergeByID() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// metaStandIn starts a stand-in YNAB API answering each request for a path
+// under the test budget with the next of its bodies. It returns a client of
+// it and the requests made, as paths with their queries.
+func metaStandIn(t *testing.T, bodies map[string][]string) (*Client, *[]string) {
+	t.Helper()
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/v1/budgets/"+testBudgetID)
+		requests = append(requests, strings.TrimSuffix(path+"?"+r.URL.RawQuery, "?"))
+		if len(bodies[path]) == 0 {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, bodies[path][0])
+		bodies[path] = bodies[path][1:]
+	}))
+	return testClient(t, srv), &requests
+}
+
+func TestCacheDeltas(t *testing.T) {
+	dir := t.TempDir()
+	budgetID := strfmt.UUID(testBudgetID)
+	c, requests := metaStandIn(t, map[string][]string{
+		"/payees": {
+			fmt.Sprintf(`{"data":{"server_knowledge":1,"payees":[{"id":%q,"name":"A"},{"id":%q,"name":"B"}]}}`, testPayee1, testPayee2),
+			// A is renamed, B deleted and C added.
+			fmt.Sprintf(`{"data":{"server_knowledge":2,"payees":[{"id":%q,"name":"A2"},{"id":%q,"name":"B","deleted":true},{"id":%q,"name":"C"}]}}`, testPayee1, testPayee2, testPayee3),
+		},
+		"/categories": {
+			fmt.Sprintf(`{"data":{"server_knowledge":1,"category_groups":[{"id":%q,"name":"Bills","categories":[{"id":%q,"name":"Rent"},{"id":%q,"name":"Power"}]}]}}`, testGroup1, testCategory1, testCategory2),
+			// Only the renamed category comes back in its group, with a new
+			// group and category.
+			fmt.Sprintf(`{"data":{"server_knowledge":2,"category_groups":[{"id":%q,"name":"Bills","categories":[{"id":%q,"name":"Electricity"}]},{"id":%q,"name":"Fun","categories":[{"id":%q,"name":"Games"}]}]}}`, testGroup1, testCategory2, testGroup2, testCategory3),
+		},
+	})
+
+	// Each run refreshes each set once, in full without a cache file and
+	// with the changes since the cached server knowledge after.
+	ctx := context.Background()
+	var payees []*models.Payee
+	var groups []*models.CategoryGroupWithCategories
+	for run := 0; run < 2; run++ {
+		cache := &Cache{Dir: dir, Client: c}
+		for i := 0; i < 2; i++ {
+			var err error
+			if payees, err = cache.Payees(ctx, &budgetID); err != nil {
+				t.Fatalf("run %d: Payees() = %v", run, err)
+			}
+			if groups, err = cache.Categories(ctx, &budgetID); err != nil {
+				t.Fatalf("run %d: Categories() = %v", run, err)
+			}
+		}
+	}
+	want := []string{
+		"/payees",
+		"/categories",
+		"/payees?last_knowledge_of_server=1",
+		"/categories?last_knowledge_of_server=1",
+	}
+	if !reflect.DeepEqual(*requests, want) {
+		t.Errorf("requests %v, want %v", *requests, want)
+	}
+	if got, want := payeeNames(payees), []string{"A2", "B (deleted)", "C"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Payees() = %v, want %v", got, want)
+	}
+	var got []string
+	for _, g := range groups {
+		for _, cat := range g.Categories {
+			got = append(got, *g.Name+": "+*cat.Name)
+		}
+	}
+	if want := []string{"Bills: Rent", "Bills: Electricity", "Fun: Games"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Categories() = %v, want %v", got, want)
+	}
+
+	// The merged sets are saved, so a third run only asks for changes since
+	// the latest knowledge.
+	c2, requests := metaStandIn(t, map[string][]string{
+		"/payees": {`{"data":{"server_knowledge":2,"payees":[]}}`},
+	})
+	cache := &Cache{Dir: dir, Client: c2}
+	if payees, err := cache.Payees(ctx, &budgetID); err != nil {
+		t.Fatalf("Payees() = %v", err)
+	} else if got, want := payeeNames(payees), []string{"A2", "B (deleted)", "C"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("cached Payees() = %v, want %v", got, want)
+	}
+	if want := []string{"/payees?last_knowledge_of_server=2"}; !reflect.DeepEqual(*requests, want) {
+		t.Errorf("requests %v, want %v", *requests, want)
+	}
+}
@@ -0,0 +1,174 @@
+// Package ynabsync syncs transactions with the YNAB API: fetching budgets,
+// accounts, categories, payees and transactions, caching budget metadata,
+// selecting budgets and accounts, matching transactions, and posting
+// transactions in resumable, rate limited batches.
+//
+// Request errors wrap the generated client's errors, such as
+// *runtime.APIError for unexpected statuses.
+package ynabsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dbinit/ynab-amazon-import/client"
+	"github.com/dbinit/ynab-amazon-import/client/accounts"
+	"github.com/dbinit/ynab-amazon-import/client/budgets"
+	"github.com/dbinit/ynab-amazon-import/client/categories"
+	"github.com/dbinit/ynab-amazon-import/client/payees"
+	"github.com/dbinit/ynab-amazon-import/client/transactions"
+	"github.com/dbinit/ynab-amazon-import/models"
+	"github.com/go-openapi/runtime"
+	httptransport "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+)
+
+// ErrNotFound is returned for a transaction that doesn't exist.
+var ErrNotFound = errors.New("not found")
+
+// Client makes YNAB API requests with a token.
+type Client struct {
+	API      *client.YNABAPIEndpoints
+	AuthInfo runtime.ClientAuthInfoWriter
+
+	// Limiter limits the requests, if it isn't nil.
+	Limiter *RateLimiter
+}
+
+// New returns a client of the default YNAB API endpoints using a personal
+// access token, limited to YNAB's rate limit.
+func New(token string) *Client {
+	return &Client{
+		API:      client.Default,
+		AuthInfo: httptransport.BearerToken(token),
+		Limiter:  NewRateLimiter(),
+	}
+}
+
+// wait waits for the rate limiter, if there is one.
+func (c *Client) wait(ctx context.Context) error {
+	if c.Limiter == nil {
+		return ctx.Err()
+	}
+	return c.Limiter.Wait(ctx)
+}
+
+// Budgets returns the budgets visible to the token, optionally with their
+// accounts, and the default budget if there is one.
+func (c *Client) Budgets(ctx context.Context, includeAccounts bool) ([]*models.BudgetSummary, *models.BudgetSummary, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, nil, err
+	}
+	params := budgets.NewGetBudgetsParamsWithContext(ctx).WithIncludeAccounts(&includeAccounts)
+	resp, err := c.API.Budgets.GetBudgets(params, c.AuthInfo)
+	if err != nil {
+		return nil, nil, fmt.Errorf("GetBudgets(): %w", err)
+	}
+	if resp == nil || resp.Payload == nil || resp.Payload.Data == nil {
+		return nil, nil, fmt.Errorf("GetBudgets(): %+v", resp)
+	}
+	return resp.Payload.Data.Budgets, resp.Payload.Data.DefaultBudget, nil
+}
+
+// Accounts returns the accounts of a budget changed since the server
+// knowledge, or all accounts if it is nil, and the new server knowledge.
+func (c *Client) Accounts(ctx context.Context, budgetID string, knowledge *int64) ([]*models.Account, int64, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, 0, err
+	}
+	params := accounts.NewGetAccountsParamsWithContext(ctx).WithBudgetID(budgetID).WithLastKnowledgeOfServer(knowledge)
+	resp, err := c.API.Accounts.GetAccounts(params, c.AuthInfo)
+	if err != nil {
+		return nil, 0, fmt.Errorf("GetAccounts(%s): %w", budgetID, err)
+	}
+	if resp == nil || resp.Payload == nil || resp.Payload.Data == nil || resp.Payload.Data.ServerKnowledge == nil {
+		return nil, 0, fmt.Errorf("GetAccounts(%s): %+v", budgetID, resp)
+	}
+	return resp.Payload.Data.Accounts, *resp.Payload.Data.ServerKnowledge, nil
+}
+
+// Categories returns the category groups of a budget changed since the server
+// knowledge, or all groups if it is nil, and the new server knowledge.
+func (c *Client) Categories(ctx context.Context, budgetID string, knowledge *int64) ([]*models.CategoryGroupWithCategories, int64, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, 0, err
+	}
+	params := categories.NewGetCategoriesParamsWithContext(ctx).WithBudgetID(budgetID).WithLastKnowledgeOfServer(knowledge)
+	resp, err := c.API.Categories.GetCategories(params, c.AuthInfo)
+	if err != nil {
+		return nil, 0, fmt.Errorf("GetCategories(%s): %w", budgetID, err)
+	}
+	if resp == nil || resp.Payload == nil || resp.Payload.Data == nil || resp.Payload.Data.ServerKnowledge == nil {
+		return nil, 0, fmt.Errorf("GetCategories(%s): %+v", budgetID, resp)
+	}
+	return resp.Payload.Data.CategoryGroups, *resp.Payload.Data.ServerKnowledge, nil
+}
+
+// Payees returns the payees of a budget changed since the server knowledge, or
+// all payees if it is nil, and the new server knowledge.
+func (c *Client) Payees(ctx context.Context, budgetID string, knowledge *int64) ([]*models.Payee, int64, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, 0, err
+	}
+	params := payees.NewGetPayeesParamsWithContext(ctx).WithBudgetID(budgetID).WithLastKnowledgeOfServer(knowledge)
+	resp, err := c.API.Payees.GetPayees(params, c.AuthInfo)
+	if err != nil {
+		return nil, 0, fmt.Errorf("GetPayees(%s): %w", budgetID, err)
+	}
+	if resp == nil || resp.Payload == nil || resp.Payload.Data == nil || resp.Payload.Data.ServerKnowledge == nil {
+		return nil, 0, fmt.Errorf("GetPayees(%s): %+v", budgetID, resp)
+	}
+	return resp.Payload.Data.Payees, *resp.Payload.Data.ServerKnowledge, nil
+}
+
+// AccountTransactions returns the transactions of an account since a date.
+func (c *Client) AccountTransactions(ctx context.Context, budgetID, accountID strfmt.UUID, since strfmt.Date) ([]*models.TransactionDetail, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+	params := transactions.NewGetTransactionsByAccountParamsWithContext(ctx).
+		WithBudgetID(budgetID.String()).
+		WithAccountID(accountID.String()).
+		WithSinceDate(&since)
+	resp, err := c.API.Transactions.GetTransactionsByAccount(params, c.AuthInfo)
+	if err != nil {
+		return nil, fmt.Errorf("GetTransactionsByAccount(): %w", err)
+	}
+	if resp == nil || resp.Payload == nil || resp.Payload.Data == nil {
+		return nil, fmt.Errorf("GetTransactionsByAccount(): %+v", resp)
+	}
+	return resp.Payload.Data.Transactions, nil
+}
+
+// Transaction returns a transaction by ID, or an error wrapping ErrNotFound.
+func (c *Client) Transaction(ctx context.Context, budgetID, transactionID string) (*models.TransactionDetail, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+	params := transactions.NewGetTransactionByIDParamsWithContext(ctx).WithBudgetID(budgetID).WithTransactionID(transactionID)
+	resp, err := c.API.Transactions.GetTransactionByID(params, c.AuthInfo)
+	var notFound *transactions.GetTransactionByIDNotFound
+	if errors.As(err, &notFound) {
+		return nil, fmt.Errorf("GetTransactionByID(%s): %w", transactionID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetTransactionByID(%s): %w", transactionID, err)
+	}
+	if resp == nil || resp.Payload == nil || resp.Payload.Data == nil || resp.Payload.Data.Transaction == nil {
+		return nil, fmt.Errorf("GetTransactionByID(%s): %+v", transactionID, resp)
+	}
+	return resp.Payload.Data.Transaction, nil
+}
+
+// DeleteTransaction deletes a transaction by ID.
+func (c *Client) DeleteTransaction(ctx context.Context, budgetID, transactionID string) error {
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+	params := transactions.NewDeleteTransactionParamsWithContext(ctx).WithBudgetID(budgetID).WithTransactionID(transactionID)
+	if _, err := c.API.Transactions.DeleteTransaction(params, c.AuthInfo); err != nil {
+		return fmt.Errorf("DeleteTransaction(%s): %w", transactionID, err)
+	}
+	return nil
+}
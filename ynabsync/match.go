@@ -0,0 +1,37 @@
+package ynabsync
+
+import (
+	"time"
+
+	"github.com/dbinit/ynab-amazon-import/models"
+)
+
+// Match returns the unused existing transaction with the same import ID, or
+// else with the same amount and the nearest date within days. Deleted
+// transactions never match.
+func Match(t *models.SaveTransaction, existing []*models.TransactionDetail, used map[string]bool, days int) *models.TransactionDetail {
+	var best *models.TransactionDetail
+	var bestDiff time.Duration
+	for _, e := range existing {
+		if e == nil || e.ID == nil || e.Amount == nil || e.Date == nil || used[*e.ID] || (e.Deleted != nil && *e.Deleted) {
+			continue
+		}
+		if e.ImportID != "" && e.ImportID == t.ImportID {
+			return e
+		}
+		if *e.Amount != *t.Amount {
+			continue
+		}
+		diff := time.Time(*e.Date).Sub(time.Time(*t.Date))
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff > time.Duration(days)*24*time.Hour {
+			continue
+		}
+		if best == nil || diff < bestDiff {
+			best, bestDiff = e, diff
+		}
+	}
+	return best
+}
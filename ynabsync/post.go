@@ -0,0 +1,356 @@
+package ynabsync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/dbinit/ynab-amazon-import/client/transactions"
+	"github.com/dbinit/ynab-amazon-import/models"
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+)
+
+const (
+	// DefaultBatchSize is the default number of transactions per request.
+	DefaultBatchSize = 100
+
+	// Backoff bounds for retried requests.
+	minBackoff = time.Second
+	maxBackoff = 5 * time.Minute
+)
+
+// ErrCheckpointMismatch is returned for a checkpoint file recording a run of
+// other transactions.
+var ErrCheckpointMismatch = errors.New("checkpoint is for a different run; remove it to start over")
+
+// Poster posts transactions to a budget in batches. Rate limited requests and
+// server errors are retried with exponential backoff, and progress may be
+// recorded in a checkpoint file so a failed run can be resumed.
+type Poster struct {
+	Client   *Client
+	BudgetID strfmt.UUID
+
+	// BatchSize is the number of transactions per request, or
+	// DefaultBatchSize if it is 0.
+	BatchSize int
+
+	// MaxRetries is the number of times to retry a batch.
+	MaxRetries int
+
+	// Checkpoint is the checkpoint file, or empty for none.
+	Checkpoint string
+
+	// Logf logs progress and retries, if it isn't nil.
+	Logf func(format string, args ...any)
+}
+
+// Result collects the results of all posted batches.
+type Result struct {
+	TransactionIDs     []string    `json:"transaction_ids"`
+	DuplicateImportIDs []string    `json:"duplicate_import_ids,omitempty"`
+	Transactions       []*Snapshot `json:"transactions,omitempty"`
+}
+
+// checkpointState records the progress of a run so it can be resumed.
+type checkpointState struct {
+	// Fingerprint identifies the transactions being posted.
+	Fingerprint string `json:"fingerprint"`
+	BudgetID    string `json:"budget_id"`
+	BatchSize   int    `json:"batch_size"`
+	Batches     int    `json:"batches"`
+	Result
+}
+
+// BatchError describes a batch that could not be posted. Code is the HTTP
+// status, if there was a response, and Detail the YNAB error, if it had one.
+type BatchError struct {
+	Batch, Batches int
+	Code           int
+	Detail         *models.ErrorDetail
+	Err            error
+}
+
+func (e *BatchError) Error() string {
+	msg := fmt.Sprintf("batch %d of %d failed", e.Batch, e.Batches)
+	if e.Code != 0 {
+		msg += fmt.Sprintf(" with status %d", e.Code)
+	}
+	if d := e.Detail; d != nil && d.Name != nil && d.Detail != nil {
+		return fmt.Sprintf("%s: %s: %s", msg, *d.Name, *d.Detail)
+	}
+	return fmt.Sprintf("%s: %v", msg, e.Err)
+}
+
+func (e *BatchError) Unwrap() error { return e.Err }
+
+// logf logs with Logf, if it is set.
+func (p *Poster) logf(format string, args ...any) {
+	if p.Logf != nil {
+		p.Logf(format, args...)
+	}
+}
+
+// Post posts the transactions in batches, resuming from the checkpoint file if
+// it records earlier progress for the same transactions. The result covers
+// the batches posted before any error, which is a *BatchError if a batch
+// failed.
+func (p *Poster) Post(ctx context.Context, txns []*models.SaveTransaction) (*Result, error) {
+	batchSize := p.BatchSize
+	if batchSize == 0 {
+		batchSize = DefaultBatchSize
+	}
+	if batchSize < 0 {
+		return nil, fmt.Errorf("invalid batch size %d", batchSize)
+	}
+	fp, err := fingerprint(txns)
+	if err != nil {
+		return nil, err
+	}
+	state, err := p.loadCheckpoint(fp, batchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	batches := (len(txns) + batchSize - 1) / batchSize
+	if state.Batches > 0 {
+		p.logf("resuming after batch %d of %d from %q", state.Batches, batches, p.Checkpoint)
+	}
+	for b := state.Batches; b < batches; b++ {
+		end := (b + 1) * batchSize
+		if end > len(txns) {
+			end = len(txns)
+		}
+		data, err := p.postBatch(ctx, txns[b*batchSize:end])
+		if err != nil {
+			berr := &BatchError{Batch: b + 1, Batches: batches, Err: err}
+			berr.Code, berr.Detail = errorDetail(err)
+			return &state.Result, berr
+		}
+		state.TransactionIDs = append(state.TransactionIDs, data.TransactionIds...)
+		state.DuplicateImportIDs = append(state.DuplicateImportIDs, data.DuplicateImportIds...)
+		if data.Transaction != nil {
+			state.Transactions = append(state.Transactions, NewSnapshot(data.Transaction))
+		}
+		for _, t := range data.Transactions {
+			state.Transactions = append(state.Transactions, NewSnapshot(t))
+		}
+		state.Batches = b + 1
+		p.logf("batch %d of %d: %d created, %d duplicates",
+			b+1, batches, len(data.TransactionIds), len(data.DuplicateImportIds))
+		if err := p.saveCheckpoint(state); err != nil {
+			return &state.Result, err
+		}
+	}
+
+	// The run is complete, so the checkpoint is no longer needed.
+	if p.Checkpoint != "" {
+		if err := os.Remove(p.Checkpoint); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return &state.Result, fmt.Errorf("os.Remove(%q): %w", p.Checkpoint, err)
+		}
+	}
+	return &state.Result, nil
+}
+
+// postBatch posts a single batch, retrying rate limited and server errors with
+// exponential backoff.
+func (p *Poster) postBatch(ctx context.Context, txns []*models.SaveTransaction) (*models.SaveTransactionsResponseData, error) {
+	params := transactions.NewCreateTransactionParamsWithContext(ctx).
+		WithBudgetID(p.BudgetID.String()).
+		WithData(&models.PostTransactionsWrapper{Transactions: txns})
+	backoff := minBackoff
+	for attempt := 0; ; attempt++ {
+		if err := p.Client.wait(ctx); err != nil {
+			return nil, err
+		}
+		resp, err := p.Client.API.Transactions.CreateTransaction(params, p.Client.AuthInfo)
+		if err == nil {
+			if resp == nil || resp.Payload == nil || resp.Payload.Data == nil {
+				return nil, fmt.Errorf("CreateTransaction(): %+v", resp)
+			}
+			return resp.Payload.Data, nil
+		}
+		delay, ok := retryDelay(err, backoff)
+		if !ok || attempt >= p.MaxRetries {
+			return nil, fmt.Errorf("CreateTransaction(): %w", err)
+		}
+		p.logf("CreateTransaction(): %v; retrying in %s", err, delay)
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// retryDelay reports whether an error is worth retrying and how long to wait.
+// A Retry-After header takes precedence over the backoff.
+func retryDelay(err error, backoff time.Duration) (time.Duration, bool) {
+	var apiErr *runtime.APIError
+	if !errors.As(err, &apiErr) {
+		return 0, false
+	}
+	if apiErr.Code != http.StatusTooManyRequests && apiErr.Code < 500 {
+		return 0, false
+	}
+	if resp, ok := apiErr.Response.(runtime.ClientResponse); ok {
+		if s, err := strconv.Atoi(resp.GetHeader("Retry-After")); err == nil && s > 0 {
+			return time.Duration(s) * time.Second, true
+		}
+	}
+	return backoff, true
+}
+
+// errorDetail extracts the status code and YNAB error detail from a
+// CreateTransaction error.
+func errorDetail(err error) (int, *models.ErrorDetail) {
+	var badRequest *transactions.CreateTransactionBadRequest
+	var conflict *transactions.CreateTransactionConflict
+	var apiErr *runtime.APIError
+	switch {
+	case errors.As(err, &badRequest):
+		if badRequest.Payload != nil {
+			return badRequest.Code(), badRequest.Payload.Error
+		}
+		return badRequest.Code(), nil
+	case errors.As(err, &conflict):
+		if conflict.Payload != nil {
+			return conflict.Code(), conflict.Payload.Error
+		}
+		return conflict.Code(), nil
+	case errors.As(err, &apiErr):
+		return apiErr.Code, nil
+	}
+	return 0, nil
+}
+
+// loadCheckpoint returns the checkpoint state for the transactions, or a new
+// state if there is no checkpoint.
+func (p *Poster) loadCheckpoint(fp string, batchSize int) (*checkpointState, error) {
+	state := &checkpointState{Fingerprint: fp, BudgetID: p.BudgetID.String(), BatchSize: batchSize}
+	if p.Checkpoint == "" {
+		return state, nil
+	}
+	b, err := os.ReadFile(p.Checkpoint)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile(%q): %w", p.Checkpoint, err)
+	}
+	var saved checkpointState
+	if err := json.Unmarshal(b, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %q: %w", p.Checkpoint, err)
+	}
+	if saved.Fingerprint != state.Fingerprint || saved.BudgetID != state.BudgetID || saved.BatchSize != state.BatchSize {
+		return nil, fmt.Errorf("%q: %w", p.Checkpoint, ErrCheckpointMismatch)
+	}
+	return &saved, nil
+}
+
+// saveCheckpoint writes the checkpoint state, if there is a checkpoint file.
+func (p *Poster) saveCheckpoint(state *checkpointState) error {
+	if p.Checkpoint == "" {
+		return nil
+	}
+	b, err := json.MarshalIndent(state, "", "\t")
+	if err != nil {
+		return fmt.Errorf("json.MarshalIndent(): %w", err)
+	}
+	// Write and rename so an interrupted write can't corrupt the checkpoint.
+	tmp := p.Checkpoint + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return fmt.Errorf("os.WriteFile(%q): %w", tmp, err)
+	}
+	if err := os.Rename(tmp, p.Checkpoint); err != nil {
+		return fmt.Errorf("os.Rename(%q): %w", tmp, err)
+	}
+	return nil
+}
+
+// fingerprint returns a hash identifying a set of transactions.
+func fingerprint(txns []*models.SaveTransaction) (string, error) {
+	b, err := json.Marshal(txns)
+	if err != nil {
+		return "", fmt.Errorf("json.Marshal(): %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Snapshot is a snapshot of a created transaction, used to detect later
+// edits. Snapshots recorded by earlier versions have no account or payee.
+type Snapshot struct {
+	ID         string `json:"id"`
+	ImportID   string `json:"import_id,omitempty"`
+	AccountID  string `json:"account_id,omitempty"`
+	Date       string `json:"date"`
+	Amount     int64  `json:"amount"`
+	PayeeID    string `json:"payee_id,omitempty"`
+	PayeeName  string `json:"payee_name,omitempty"`
+	Memo       string `json:"memo,omitempty"`
+	CategoryID string `json:"category_id,omitempty"`
+}
+
+// NewSnapshot returns a snapshot of a transaction.
+func NewSnapshot(t *models.TransactionDetail) *Snapshot {
+	s := &Snapshot{
+		ImportID:   t.ImportID,
+		PayeeID:    t.PayeeID.String(),
+		PayeeName:  t.PayeeName,
+		Memo:       t.Memo,
+		CategoryID: t.CategoryID.String(),
+	}
+	if t.ID != nil {
+		s.ID = *t.ID
+	}
+	if t.AccountID != nil {
+		s.AccountID = t.AccountID.String()
+	}
+	if t.Date != nil {
+		s.Date = t.Date.String()
+	}
+	if t.Amount != nil {
+		s.Amount = *t.Amount
+	}
+	return s
+}
+
+// Changes describes how a transaction differs from its snapshot. The payee is
+// compared by ID, as renaming a payee renames it in every transaction, or by
+// name if the snapshot has no payee ID. The account and payee aren't compared
+// for snapshots without them.
+func (s *Snapshot) Changes(t *models.TransactionDetail) []string {
+	now := NewSnapshot(t)
+	var changes []string
+	if s.AccountID != "" && now.AccountID != s.AccountID {
+		changes = append(changes, fmt.Sprintf("account %s -> %s", s.AccountID, now.AccountID))
+	}
+	if now.Date != s.Date {
+		changes = append(changes, fmt.Sprintf("date %s -> %s", s.Date, now.Date))
+	}
+	if now.Amount != s.Amount {
+		changes = append(changes, fmt.Sprintf("amount %d -> %d", s.Amount, now.Amount))
+	}
+	if (s.PayeeID != "" && now.PayeeID != s.PayeeID) || (s.PayeeID == "" && s.PayeeName != "" && now.PayeeName != s.PayeeName) {
+		changes = append(changes, fmt.Sprintf("payee %q -> %q", s.PayeeName, now.PayeeName))
+	}
+	if now.Memo != s.Memo {
+		changes = append(changes, fmt.Sprintf("memo %q -> %q", s.Memo, now.Memo))
+	}
+	if now.CategoryID != s.CategoryID {
+		changes = append(changes, fmt.Sprintf("category %q -> %q", s.CategoryID, now.CategoryID))
+	}
+	if t.MatchedTransactionID != "" {
+		changes = append(changes, "matched to transaction "+t.MatchedTransactionID)
+	}
+	return changes
+}
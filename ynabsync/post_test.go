@@ -0,0 +1,299 @@
+package ynabsync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dbinit/ynab-amazon-import/client"
+	"github.com/dbinit/ynab-amazon-import/models"
+	httptransport "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+)
+
+const testBudgetID = "11111111-1111-1111-1111-111111111111"
+
+// reply is a stand-in response to a request to create transactions: an error
+// status, or 0 to create them. Requests beyond the planned replies create the
+// transactions.
+type reply struct {
+	status     int
+	retryAfter string
+}
+
+// standIn starts a stand-in YNAB API answering requests to create
+// transactions with the replies in turn. It returns a client of it and the
+// import IDs of each request.
+func standIn(t *testing.T, replies ...reply) (*Client, *[][]string) {
+	t.Helper()
+	var requests [][]string
+	created := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/budgets/"+testBudgetID+"/transactions" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var req models.PostTransactionsWrapper
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("request body: %v", err)
+		}
+		var ids []string
+		for _, st := range req.Transactions {
+			ids = append(ids, st.ImportID)
+		}
+		requests = append(requests, ids)
+
+		w.Header().Set("Content-Type", "application/json")
+		if n := len(requests) - 1; n < len(replies) && replies[n].status != 0 {
+			if replies[n].retryAfter != "" {
+				w.Header().Set("Retry-After", replies[n].retryAfter)
+			}
+			w.WriteHeader(replies[n].status)
+			fmt.Fprintf(w, `{"error":{"id":"%d","name":"error_%d","detail":"stand-in error"}}`, replies[n].status, replies[n].status)
+			return
+		}
+		var resp struct {
+			Data struct {
+				TransactionIDs  []string `json:"transaction_ids"`
+				ServerKnowledge int64    `json:"server_knowledge"`
+			} `json:"data"`
+		}
+		for range req.Transactions {
+			created++
+			resp.Data.TransactionIDs = append(resp.Data.TransactionIDs, fmt.Sprintf("t%d", created))
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(&resp)
+	}))
+	return testClient(t, srv), &requests
+}
+
+// testClient returns a client of a stand-in YNAB API, closing it when the test
+// ends.
+func testClient(t *testing.T, srv *httptest.Server) *Client {
+	t.Helper()
+	t.Cleanup(srv.Close)
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := client.New(httptransport.New(u.Host, "/v1", []string{u.Scheme}), strfmt.Default)
+	return &Client{API: api, AuthInfo: httptransport.BearerToken("test")}
+}
+
+// recordSleeps replaces sleep for the test, recording the waits.
+func recordSleeps(t *testing.T) *[]time.Duration {
+	var waits []time.Duration
+	def := sleep
+	t.Cleanup(func() { sleep = def })
+	sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return ctx.Err()
+	}
+	return &waits
+}
+
+// testTransactions returns n transactions with import IDs "A1", "A2", ....
+func testTransactions(n int) []*models.SaveTransaction {
+	var txns []*models.SaveTransaction
+	for i := 1; i <= n; i++ {
+		date := strfmt.Date(time.Date(2023, 1, i, 0, 0, 0, 0, time.UTC))
+		amount := int64(-1000 * i)
+		t := &models.SaveTransaction{Date: &date, Amount: &amount}
+		t.ImportID = fmt.Sprintf("A%d", i)
+		txns = append(txns, t)
+	}
+	return txns
+}
+
+func TestPost(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		txns       int
+		batchSize  int
+		maxRetries int
+		replies    []reply
+		requests   [][]string
+		waits      []time.Duration
+		ids        int
+		code       int
+	}{
+		{
+			name:      "batches",
+			txns:      5,
+			batchSize: 2,
+			requests:  [][]string{{"A1", "A2"}, {"A3", "A4"}, {"A5"}},
+			ids:       5,
+		},
+		{
+			name:       "backoff",
+			txns:       1,
+			maxRetries: 5,
+			replies:    []reply{{status: 500}, {status: 502}, {status: 503}},
+			requests:   [][]string{{"A1"}, {"A1"}, {"A1"}, {"A1"}},
+			waits:      []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+			ids:        1,
+		},
+		{
+			name:       "retry after",
+			txns:       1,
+			maxRetries: 5,
+			replies:    []reply{{status: 429, retryAfter: "30"}},
+			requests:   [][]string{{"A1"}, {"A1"}},
+			waits:      []time.Duration{30 * time.Second},
+			ids:        1,
+		},
+		{
+			name:       "out of retries",
+			txns:       3,
+			batchSize:  2,
+			maxRetries: 1,
+			replies:    []reply{{}, {status: 429}, {status: 429}},
+			requests:   [][]string{{"A1", "A2"}, {"A3"}, {"A3"}},
+			waits:      []time.Duration{time.Second},
+			ids:        2,
+			code:       429,
+		},
+		{
+			name:       "not retried",
+			txns:       1,
+			maxRetries: 5,
+			replies:    []reply{{status: 400}},
+			requests:   [][]string{{"A1"}},
+			code:       400,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			waits := recordSleeps(t)
+			c, requests := standIn(t, tc.replies...)
+			p := &Poster{Client: c, BudgetID: testBudgetID, BatchSize: tc.batchSize, MaxRetries: tc.maxRetries}
+			res, err := p.Post(context.Background(), testTransactions(tc.txns))
+			var berr *BatchError
+			switch {
+			case tc.code == 0 && err != nil:
+				t.Fatalf("Post() = %v", err)
+			case tc.code != 0 && !errors.As(err, &berr):
+				t.Fatalf("Post() = %v, want a *BatchError", err)
+			case berr != nil && berr.Code != tc.code:
+				t.Errorf("Post() = %v, want status %d", err, tc.code)
+			}
+			if !reflect.DeepEqual(*requests, tc.requests) {
+				t.Errorf("Post() requests %v, want %v", *requests, tc.requests)
+			}
+			if !reflect.DeepEqual(*waits, tc.waits) {
+				t.Errorf("Post() waits %v, want %v", *waits, tc.waits)
+			}
+			if len(res.TransactionIDs) != tc.ids {
+				t.Errorf("Post() created %v, want %d transactions", res.TransactionIDs, tc.ids)
+			}
+		})
+	}
+}
+
+func TestPostCheckpoint(t *testing.T) {
+	recordSleeps(t)
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint.json")
+	txns := testTransactions(3)
+
+	// The second batch fails, leaving a checkpoint after the first.
+	c, requests := standIn(t, reply{}, reply{status: 400})
+	p := &Poster{Client: c, BudgetID: testBudgetID, BatchSize: 1, Checkpoint: checkpoint}
+	if _, err := p.Post(context.Background(), txns); err == nil {
+		t.Fatal("Post() succeeded, want the second batch to fail")
+	}
+	if _, err := os.Stat(checkpoint); err != nil {
+		t.Fatalf("no checkpoint after a failed batch: %v", err)
+	}
+
+	// Other transactions don't resume the checkpoint.
+	if _, err := p.Post(context.Background(), testTransactions(2)); !errors.Is(err, ErrCheckpointMismatch) {
+		t.Errorf("Post() of other transactions = %v, want %v", err, ErrCheckpointMismatch)
+	}
+
+	// The same transactions resume after the first batch, and the
+	// checkpoint is removed once they are all posted.
+	p.Client, requests = standIn(t)
+	res, err := p.Post(context.Background(), txns)
+	if err != nil {
+		t.Fatalf("resumed Post() = %v", err)
+	}
+	if want := [][]string{{"A2"}, {"A3"}}; !reflect.DeepEqual(*requests, want) {
+		t.Errorf("resumed Post() requests %v, want %v", *requests, want)
+	}
+	if len(res.TransactionIDs) != 3 {
+		t.Errorf("resumed Post() created %v, want all 3 transactions", res.TransactionIDs)
+	}
+	if _, err := os.Stat(checkpoint); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("checkpoint left after the run completed: %v", err)
+	}
+}
+
+func TestSnapshotChanges(t *testing.T) {
+	const (
+		account1 = "22222222-2222-2222-2222-222222222221"
+		account2 = "22222222-2222-2222-2222-222222222222"
+	)
+	// detail returns a transaction as created, changed by edit.
+	detail := func(edit func(t *models.TransactionDetail)) *models.TransactionDetail {
+		t := &models.TransactionDetail{}
+		t.ID = ptrTo("t1")
+		t.AccountID = ptrTo(strfmt.UUID(account1))
+		t.Date = ptrTo(strfmt.Date(time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC)))
+		t.Amount = ptrTo(int64(-5000))
+		t.PayeeID = testPayee1
+		t.PayeeName = "Amazon"
+		t.Memo = "Widget"
+		if edit != nil {
+			edit(t)
+		}
+		return t
+	}
+	snapshot := NewSnapshot(detail(nil))
+	// An earlier version's snapshot has no account or payee.
+	older := *snapshot
+	older.AccountID, older.PayeeID, older.PayeeName = "", "", ""
+	// A snapshot by payee name alone.
+	byName := *snapshot
+	byName.PayeeID = ""
+
+	for _, tc := range []struct {
+		name     string
+		snapshot *Snapshot
+		edit     func(t *models.TransactionDetail)
+		want     []string
+	}{
+		{"unchanged", snapshot, nil, nil},
+		{"account", snapshot, func(t *models.TransactionDetail) { t.AccountID = ptrTo(strfmt.UUID(account2)) },
+			[]string{"account " + account1 + " -> " + account2}},
+		{"payee", snapshot, func(t *models.TransactionDetail) { t.PayeeID, t.PayeeName = testPayee2, "Costco" },
+			[]string{`payee "Amazon" -> "Costco"`}},
+		// Renaming the payee renames it in every transaction, which isn't an
+		// edit of this one.
+		{"payee renamed", snapshot, func(t *models.TransactionDetail) { t.PayeeName = "Amazon.com" }, nil},
+		{"payee name", &byName, func(t *models.TransactionDetail) { t.PayeeName = "Costco" },
+			[]string{`payee "Amazon" -> "Costco"`}},
+		{"older snapshot", &older, func(t *models.TransactionDetail) {
+			t.AccountID, t.PayeeID, t.PayeeName = ptrTo(strfmt.UUID(account2)), testPayee2, "Costco"
+		}, nil},
+		{"amount and memo", snapshot, func(t *models.TransactionDetail) { t.Amount, t.Memo = ptrTo(int64(-6000)), "Gadget" },
+			[]string{"amount -5000 -> -6000", `memo "Widget" -> "Gadget"`}},
+		{"matched", snapshot, func(t *models.TransactionDetail) { t.MatchedTransactionID = "t2" },
+			[]string{"matched to transaction t2"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.snapshot.Changes(detail(tc.edit)); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Changes() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
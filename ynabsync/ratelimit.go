@@ -0,0 +1,81 @@
+package ynabsync
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// RateLimit requests per RateLimitWindow is YNAB's limit per access token,
+	// over a rolling hour.
+	RateLimit       = 200
+	RateLimitWindow = time.Hour
+)
+
+// RateLimiter limits requests to a number per rolling window. It is safe for
+// concurrent use. The requests are only counted within the process, so a run
+// resumed from a checkpoint starts counting afresh, and YNAB may still answer
+// 429 if the earlier run used up the window.
+type RateLimiter struct {
+	Limit  int
+	Window time.Duration
+
+	// Logf logs waits, if it isn't nil.
+	Logf func(format string, args ...any)
+
+	mu   sync.Mutex
+	sent []time.Time
+}
+
+// NewRateLimiter returns a rate limiter for YNAB's rate limit.
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{Limit: RateLimit, Window: RateLimitWindow}
+}
+
+// Wait blocks until another request is allowed and records it, or returns the
+// context's error if it is done first. The lock isn't held while waiting, so
+// other callers can give up on their own contexts meanwhile.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		d := l.reserve(time.Now())
+		if d <= 0 {
+			return nil
+		}
+		if l.Logf != nil {
+			l.Logf("rate limit of %d requests per %s reached; waiting %s", l.Limit, l.Window, d)
+		}
+		if err := sleep(ctx, d); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve records a request at now if one is allowed, or else returns how
+// long until the oldest request leaves the window.
+func (l *RateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// Forget requests outside the window.
+	for len(l.sent) > 0 && now.Sub(l.sent[0]) >= l.Window {
+		l.sent = l.sent[1:]
+	}
+	if len(l.sent) >= l.Limit {
+		return l.Window - now.Sub(l.sent[0])
+	}
+	l.sent = append(l.sent, now)
+	return 0
+}
+
+// sleep waits for a duration, or returns the context's error if it is done
+// first. Tests replace it to record the waits instead.
+var sleep = func(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,88 @@
+package ynabsync
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dbinit/ynab-amazon-import/models"
+	"github.com/go-openapi/strfmt"
+)
+
+// Budget selectors resolved by the YNAB API.
+const (
+	LastUsedBudget = "last-used"
+	DefaultBudget  = "default"
+)
+
+// FindBudget returns the budget selected by ID, by name, or as the default
+// budget. Budgets sharing the name are ambiguous. The last-used budget must
+// already be resolved to its ID.
+func FindBudget(bs []*models.BudgetSummary, def *models.BudgetSummary, budget string) (*models.BudgetSummary, error) {
+	switch {
+	case budget == DefaultBudget:
+		if def == nil || def.ID == nil {
+			return nil, fmt.Errorf("no default budget is selected")
+		}
+		return FindBudget(bs, nil, def.ID.String())
+	case strfmt.IsUUID(budget):
+		for _, b := range bs {
+			if b != nil && b.ID != nil && b.Name != nil && strings.EqualFold(b.ID.String(), budget) {
+				return b, nil
+			}
+		}
+		return nil, fmt.Errorf("budget ID %s not found", budget)
+	}
+
+	var matches []*models.BudgetSummary
+	for _, b := range bs {
+		if b != nil && b.ID != nil && b.Name != nil && strings.EqualFold(*b.Name, budget) {
+			matches = append(matches, b)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("budget %q not found", budget)
+	case 1:
+		return matches[0], nil
+	}
+	ids := make([]string, len(matches))
+	for i, b := range matches {
+		ids[i] = b.ID.String()
+	}
+	return nil, fmt.Errorf("budget %q is ambiguous; use one of the IDs %s", budget, strings.Join(ids, ", "))
+}
+
+// FindAccount returns the open account of a budget selected by ID or name.
+// Open accounts sharing the name are ambiguous, and closed or deleted accounts
+// are errors.
+func FindAccount(b *models.BudgetSummary, as []*models.Account, account string) (*models.Account, error) {
+	var matches, live []*models.Account
+	for _, a := range as {
+		if a == nil || a.ID == nil || a.Name == nil {
+			continue
+		}
+		if strfmt.IsUUID(account) && strings.EqualFold(a.ID.String(), account) || strings.EqualFold(*a.Name, account) {
+			matches = append(matches, a)
+			if a.Deleted == nil || !*a.Deleted {
+				live = append(live, a)
+			}
+		}
+	}
+	switch {
+	case len(matches) == 0:
+		return nil, fmt.Errorf("account %q not found in budget %q", account, *b.Name)
+	case len(live) == 0:
+		return nil, fmt.Errorf("account %q in budget %q is deleted", account, *b.Name)
+	case len(live) > 1:
+		ids := make([]string, len(live))
+		for i, a := range live {
+			ids[i] = a.ID.String()
+		}
+		return nil, fmt.Errorf("account %q is ambiguous in budget %q; use one of the IDs %s", account, *b.Name, strings.Join(ids, ", "))
+	}
+	a := live[0]
+	if a.Closed != nil && *a.Closed {
+		return nil, fmt.Errorf("account %q in budget %q is closed", *a.Name, *b.Name)
+	}
+	return a, nil
+}